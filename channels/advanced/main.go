@@ -3,6 +3,8 @@ package main
 import (
 	"fmt"
 	"time"
+
+	"sugar/clock"
 )
 
 func closingChannels() {
@@ -131,14 +133,17 @@ func selectWithDefault() {
 	}
 }
 
-func selectWithTimeout() {
+// selectWithTimeout races a sender that sleeps 2 seconds on clk against a
+// 1-second clk.After. main passes clock.Real; the test advances a FakeClock
+// past the timeout, so the sender loses without anyone waiting a second.
+func selectWithTimeout(clk clock.Clock) {
 	fmt.Println("\n=== 6. SELECT WITH TIMEOUT ===")
 
 	ch := make(chan string)
 
 	// Goroutine that sends after 2 seconds
 	go func() {
-		time.Sleep(2 * time.Second)
+		clk.Sleep(2 * time.Second)
 		ch <- "Data arrived"
 	}()
 
@@ -146,7 +151,7 @@ func selectWithTimeout() {
 	select {
 	case msg := <-ch:
 		fmt.Println("Received:", msg)
-	case <-time.After(1 * time.Second):
+	case <-clk.After(1 * time.Second):
 		fmt.Println("Timeout! No data received in 1 second")
 	}
 }
@@ -174,6 +179,7 @@ func selectInLoop() {
 				return
 			}
 			fmt.Printf("Received: %d\n", val)
+		//sugarlint:ignore timeafter the pattern channels/timeouts replaces with a reused timer
 		case <-time.After(1 * time.Second):
 			fmt.Println("Timeout waiting for data")
 			return
@@ -314,6 +320,7 @@ func fanInPattern() {
 
 	// Fan-in: merge multiple channels into one
 	fanIn := func(channels ...<-chan int) <-chan int {
+		//sugarlint:ignore fanin the unclosed fan-in this example illustrates: the reader stops on a timeout
 		out := make(chan int)
 
 		for _, ch := range channels {
@@ -389,7 +396,7 @@ func main() {
 	selectWithDefault()
 	time.Sleep(300 * time.Millisecond)
 
-	selectWithTimeout()
+	selectWithTimeout(clock.Real)
 	time.Sleep(300 * time.Millisecond)
 
 	selectInLoop()
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"sugar/channels/leakcheck"
+	"sugar/clock"
+)
+
+// Examples run one at a time: leakcheck compares goroutine snapshots, so a
+// parallel test would look like a leak.
+func TestExamplesDoNotLeakOrDeadlock(t *testing.T) {
+	leakcheck.Check(t,
+		leakcheck.Example{Name: "closingChannels", Fn: closingChannels},
+		leakcheck.Example{Name: "rangeOverClosedChannel", Fn: rangeOverClosedChannel},
+		// The sender that loses the select stays blocked on its unbuffered
+		// channel - the leak this example is about
+		leakcheck.Example{Name: "basicSelect", Fn: basicSelect,
+			Opts: []leakcheck.Option{leakcheck.IgnoreFunction("sugar/channels/advanced.basicSelect")}},
+		leakcheck.Example{Name: "selectMultipleReady", Fn: selectMultipleReady},
+		leakcheck.Example{Name: "selectWithDefault", Fn: selectWithDefault},
+		// Same for the sender that arrives after the timeout
+		leakcheck.Example{Name: "selectWithTimeout", Fn: selectWithTimeoutOnFakeClock,
+			Opts: []leakcheck.Option{leakcheck.IgnoreFunction("sugar/channels/advanced.selectWithTimeout")}},
+		leakcheck.Example{Name: "selectInLoop", Fn: selectInLoop},
+		leakcheck.Example{Name: "directionalChannels", Fn: directionalChannels},
+		leakcheck.Example{Name: "selectWithSend", Fn: selectWithSend},
+		leakcheck.Example{Name: "nilChannelBehavior", Fn: nilChannelBehavior},
+		leakcheck.Example{Name: "workerPoolPattern", Fn: workerPoolPattern},
+		leakcheck.Example{Name: "fanInPattern", Fn: fanInPattern},
+		leakcheck.Example{Name: "pingPongPattern", Fn: pingPongPattern},
+	)
+}
+
+// selectWithTimeoutOnFakeClock runs the timeout example without waiting
+// for its real second.
+func selectWithTimeoutOnFakeClock() {
+	clk := clock.NewFakeClock(time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC))
+
+	done := make(chan struct{})
+	go func() {
+		selectWithTimeout(clk)
+		close(done)
+	}()
+
+	clk.BlockUntil(2) // The sender's Sleep and the select's After
+	clk.Advance(time.Second)
+	<-done
+}
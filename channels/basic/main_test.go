@@ -0,0 +1,20 @@
+package main
+
+import (
+	"testing"
+
+	"sugar/channels/leakcheck"
+)
+
+// Examples run one at a time: leakcheck compares goroutine snapshots, so a
+// parallel test would look like a leak.
+func TestExamplesDoNotLeakOrDeadlock(t *testing.T) {
+	leakcheck.Check(t,
+		leakcheck.Example{Name: "unbufferedChannel", Fn: unbufferedChannel},
+		leakcheck.Example{Name: "bufferedChannel", Fn: bufferedChannel},
+		leakcheck.Example{Name: "goroutineCommunication", Fn: goroutineCommunication},
+		leakcheck.Example{Name: "multipleGoroutines", Fn: multipleGoroutines},
+		leakcheck.Example{Name: "blockingDemo", Fn: blockingDemo},
+		leakcheck.Example{Name: "channelTypes", Fn: channelTypes},
+	)
+}
@@ -0,0 +1,324 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// --- 1. Slow-Consumer Policies ---
+
+// Policy decides what Publish does when a subscriber's buffer is full.
+type Policy int
+
+const (
+	Block      Policy = iota // Wait until the subscriber has room (slows everyone down)
+	DropOldest               // Discard the oldest buffered message to make room
+	DropNewest               // Discard the message being published
+	Disconnect               // Unsubscribe the slow consumer and close its channel
+)
+
+func (p Policy) String() string {
+	switch p {
+	case Block:
+		return "block"
+	case DropOldest:
+		return "drop-oldest"
+	case DropNewest:
+		return "drop-newest"
+	case Disconnect:
+		return "disconnect"
+	}
+	return fmt.Sprintf("Policy(%d)", int(p))
+}
+
+// ErrClosed is returned when publishing to or subscribing on a closed hub.
+var ErrClosed = errors.New("broadcast: hub is closed")
+
+// --- 2. Subscriptions ---
+
+// Subscription is one consumer's view of the hub.
+type Subscription[T any] struct {
+	ch     chan T
+	policy Policy
+	topics []string // Empty means every topic
+
+	// gone is closed by Unsubscribe before it takes the hub lock, so a
+	// Publish blocked on this subscriber (Block policy) can give up
+	gone  chan struct{}
+	leave sync.Once
+}
+
+// C returns the channel messages are delivered on. It is closed when the
+// subscriber is removed or the hub is closed.
+func (s *Subscription[T]) C() <-chan T {
+	return s.ch
+}
+
+// matches reports whether the subscription wants messages on topic.
+func (s *Subscription[T]) matches(topic string) bool {
+	if len(s.topics) == 0 {
+		return true // No filter: every topic
+	}
+	for _, pattern := range s.topics {
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+			if strings.HasPrefix(topic, prefix) {
+				return true
+			}
+		} else if pattern == topic {
+			return true
+		}
+	}
+	return false
+}
+
+// --- 3. The Broadcaster ---
+
+// Broadcaster delivers every published message to every subscriber,
+// unlike a plain channel where each value goes to exactly one receiver.
+type Broadcaster[T any] struct {
+	mu     sync.RWMutex
+	subs   map[*Subscription[T]]struct{}
+	closed bool
+
+	done      chan struct{} // Closed by Close before it takes the lock, like Subscription.gone
+	closeOnce sync.Once
+}
+
+// NewBroadcaster creates an empty hub.
+func NewBroadcaster[T any]() *Broadcaster[T] {
+	return &Broadcaster[T]{
+		subs: make(map[*Subscription[T]]struct{}),
+		done: make(chan struct{}),
+	}
+}
+
+// Subscribe adds a consumer with a buffer of bufSize messages and the
+// given slow-consumer policy. DropOldest needs bufSize of at least 1.
+// If topics are given, only messages on those topics are delivered;
+// a trailing "*" matches by prefix, as in "config.*".
+func (b *Broadcaster[T]) Subscribe(bufSize int, policy Policy, topics ...string) (*Subscription[T], error) {
+	if policy == DropOldest && bufSize < 1 {
+		return nil, fmt.Errorf("broadcast: %s policy needs a buffer, got size %d", policy, bufSize)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return nil, ErrClosed
+	}
+
+	sub := &Subscription[T]{
+		ch:     make(chan T, bufSize),
+		policy: policy,
+		topics: topics,
+		gone:   make(chan struct{}),
+	}
+	b.subs[sub] = struct{}{}
+	return sub, nil
+}
+
+// Unsubscribe removes sub and closes its channel. It is safe to call twice.
+func (b *Broadcaster[T]) Unsubscribe(sub *Subscription[T]) {
+	sub.leave.Do(func() { close(sub.gone) })
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.remove(sub)
+}
+
+// remove must be called with b.mu held for writing.
+func (b *Broadcaster[T]) remove(sub *Subscription[T]) {
+	if _, ok := b.subs[sub]; !ok {
+		return
+	}
+	delete(b.subs, sub)
+	close(sub.ch)
+}
+
+// Publish sends msg on topic to every matching subscriber.
+func (b *Broadcaster[T]) Publish(topic string, msg T) error {
+	// Publishing holds the write lock: it keeps per-subscriber order intact,
+	// and the Disconnect policy needs to remove subscribers along the way.
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return ErrClosed
+	}
+
+	for sub := range b.subs {
+		if !sub.matches(topic) {
+			continue
+		}
+		b.deliver(sub, msg)
+	}
+	return nil
+}
+
+func (b *Broadcaster[T]) deliver(sub *Subscription[T], msg T) {
+	switch sub.policy {
+	case Block:
+		select {
+		case sub.ch <- msg:
+		case <-sub.gone: // Subscriber is leaving, stop waiting for it
+		case <-b.done: // Hub is closing
+		}
+
+	case DropOldest:
+		for {
+			select {
+			case sub.ch <- msg:
+				return
+			default:
+			}
+			// Buffer full: throw away the oldest message and try again
+			select {
+			case <-sub.ch:
+			default:
+			}
+		}
+
+	case DropNewest:
+		select {
+		case sub.ch <- msg:
+		default:
+		}
+
+	case Disconnect:
+		select {
+		case sub.ch <- msg:
+		default:
+			b.remove(sub)
+		}
+	}
+}
+
+// Subscribers returns the number of active subscriptions.
+func (b *Broadcaster[T]) Subscribers() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.subs)
+}
+
+// Close removes every subscriber, closing their channels so that
+// `for msg := range sub.C()` loops end. Later calls do nothing.
+func (b *Broadcaster[T]) Close() {
+	b.closeOnce.Do(func() { close(b.done) })
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return
+	}
+	b.closed = true
+	for sub := range b.subs {
+		b.remove(sub)
+	}
+}
+
+// --- 4. Examples ---
+
+func everySubscriberGetsEveryMessage() {
+	fmt.Println("\n=== 1. FAN-OUT: EVERY SUBSCRIBER GETS EVERY MESSAGE ===")
+
+	hub := NewBroadcaster[string]()
+
+	var wg sync.WaitGroup
+	for i := 1; i <= 3; i++ {
+		sub, _ := hub.Subscribe(4, Block)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for msg := range sub.C() {
+				fmt.Printf("Subscriber %d got: %s\n", i, msg)
+			}
+			fmt.Printf("Subscriber %d: channel closed\n", i)
+		}()
+	}
+
+	hub.Publish("config.db", "max_conns=50")
+	hub.Publish("config.cache", "ttl=30s")
+	hub.Close() // Ends every range loop above
+
+	wg.Wait()
+}
+
+func topicFiltering() {
+	fmt.Println("\n=== 2. TOPIC FILTERING ===")
+
+	hub := NewBroadcaster[string]()
+	defer hub.Close()
+
+	dbOnly, _ := hub.Subscribe(4, Block, "config.db")
+	allConfig, _ := hub.Subscribe(4, Block, "config.*")
+
+	hub.Publish("config.db", "max_conns=50")
+	hub.Publish("config.cache", "ttl=30s")
+	hub.Publish("metrics", "cpu=42%")
+
+	fmt.Printf("db-only subscriber buffered %d message(s)\n", len(dbOnly.C()))
+	fmt.Printf("config.* subscriber buffered %d message(s)\n", len(allConfig.C()))
+}
+
+func slowConsumerPolicies() {
+	fmt.Println("\n=== 3. SLOW-CONSUMER POLICIES ===")
+
+	for _, policy := range []Policy{DropOldest, DropNewest, Disconnect} {
+		hub := NewBroadcaster[int]()
+		sub, _ := hub.Subscribe(2, policy)
+
+		// Nobody is reading, so the 2-slot buffer overflows
+		for i := 1; i <= 5; i++ {
+			hub.Publish("numbers", i)
+		}
+		hub.Close()
+
+		var got []int
+		for v := range sub.C() {
+			got = append(got, v)
+		}
+		fmt.Printf("%-12s kept %v\n", policy.String()+":", got)
+	}
+}
+
+func blockPolicy() {
+	fmt.Println("\n=== 4. BLOCK POLICY ===")
+
+	hub := NewBroadcaster[int]()
+	sub, _ := hub.Subscribe(0, Block)
+
+	go func() {
+		for i := 1; i <= 3; i++ {
+			hub.Publish("numbers", i) // Waits for the reader every time
+		}
+		hub.Close()
+	}()
+
+	for v := range sub.C() {
+		fmt.Println("Received:", v)
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+func main() {
+	fmt.Println("📣 GO BROADCAST HUB - COMPLETE GUIDE")
+	fmt.Println("====================================")
+
+	everySubscriberGetsEveryMessage()
+	time.Sleep(300 * time.Millisecond)
+
+	topicFiltering()
+	time.Sleep(300 * time.Millisecond)
+
+	slowConsumerPolicies()
+	time.Sleep(300 * time.Millisecond)
+
+	blockPolicy()
+
+	fmt.Println("\n✅ All examples completed!")
+}
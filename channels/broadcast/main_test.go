@@ -0,0 +1,180 @@
+package main
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"sugar/channels/leakcheck"
+)
+
+// drain collects what sub still holds once its channel is closed.
+func drain[T any](sub *Subscription[T]) []T {
+	var got []T
+	for v := range sub.C() {
+		got = append(got, v)
+	}
+	return got
+}
+
+func TestOverflowPolicies(t *testing.T) {
+	tests := []struct {
+		policy Policy
+		want   []int
+	}{
+		{DropOldest, []int{4, 5}},
+		{DropNewest, []int{1, 2}},
+		{Disconnect, []int{1, 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.policy.String(), func(t *testing.T) {
+			hub := NewBroadcaster[int]()
+			sub, err := hub.Subscribe(2, tt.policy)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i := 1; i <= 5; i++ {
+				if err := hub.Publish("n", i); err != nil {
+					t.Fatalf("Publish(%d) = %v", i, err)
+				}
+			}
+			if tt.policy == Disconnect {
+				if n := hub.Subscribers(); n != 0 {
+					t.Errorf("Subscribers() = %d after overflow, want the slow one gone", n)
+				}
+			}
+			hub.Close()
+
+			if got := drain(sub); !slices.Equal(got, tt.want) {
+				t.Errorf("kept %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDropOldestNeedsBuffer(t *testing.T) {
+	hub := NewBroadcaster[int]()
+	defer hub.Close()
+	if _, err := hub.Subscribe(0, DropOldest); err == nil {
+		t.Fatal("Subscribe(0, DropOldest) succeeded")
+	}
+}
+
+func TestBlockWaitsForReader(t *testing.T) {
+	hub := NewBroadcaster[int]()
+	defer hub.Close()
+	sub, _ := hub.Subscribe(1, Block)
+	hub.Publish("n", 1) // Fills the buffer
+
+	published := make(chan error)
+	go func() { published <- hub.Publish("n", 2) }()
+
+	select {
+	case <-published:
+		t.Fatal("Publish returned while the subscriber's buffer was full")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	if v := <-sub.C(); v != 1 {
+		t.Fatalf("first message = %d, want 1", v)
+	}
+	if err := <-published; err != nil {
+		t.Fatalf("Publish() = %v once the reader caught up", err)
+	}
+	if v := <-sub.C(); v != 2 {
+		t.Fatalf("second message = %d, want 2", v)
+	}
+}
+
+func TestUnsubscribeReleasesBlockedPublish(t *testing.T) {
+	hub := NewBroadcaster[int]()
+	defer hub.Close()
+	sub, _ := hub.Subscribe(0, Block)
+
+	published := make(chan error)
+	go func() { published <- hub.Publish("n", 1) }()
+	time.Sleep(10 * time.Millisecond) // Let Publish block on the subscriber
+
+	hub.Unsubscribe(sub)
+	hub.Unsubscribe(sub) // Safe to call twice
+
+	if err := <-published; err != nil {
+		t.Fatalf("Publish() = %v, want nil after the subscriber left", err)
+	}
+	if _, ok := <-sub.C(); ok {
+		t.Error("channel still open after Unsubscribe")
+	}
+}
+
+func TestTopicFilters(t *testing.T) {
+	hub := NewBroadcaster[string]()
+	exact, _ := hub.Subscribe(8, Block, "config.db")
+	prefix, _ := hub.Subscribe(8, Block, "config.*")
+	every, _ := hub.Subscribe(8, Block)
+
+	for _, topic := range []string{"config.db", "config.cache", "metrics", "configdb"} {
+		hub.Publish(topic, topic)
+	}
+	hub.Close()
+
+	tests := []struct {
+		name string
+		sub  *Subscription[string]
+		want []string
+	}{
+		{"exact", exact, []string{"config.db"}},
+		{"prefix", prefix, []string{"config.db", "config.cache"}},
+		{"unfiltered", every, []string{"config.db", "config.cache", "metrics", "configdb"}},
+	}
+	for _, tt := range tests {
+		if got := drain(tt.sub); !slices.Equal(got, tt.want) {
+			t.Errorf("%s subscriber got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCloseClosesSubscribers(t *testing.T) {
+	hub := NewBroadcaster[int]()
+	subs := make([]*Subscription[int], 3)
+	for i := range subs {
+		subs[i], _ = hub.Subscribe(1, Block)
+	}
+
+	// A Publish blocked on a full subscriber must not hold up Close
+	hub.Publish("n", 1)
+	published := make(chan error)
+	go func() { published <- hub.Publish("n", 2) }()
+	time.Sleep(10 * time.Millisecond)
+
+	hub.Close()
+	hub.Close() // Later calls do nothing
+	<-published
+
+	for i, sub := range subs {
+		drain(sub) // Returns only because the channel is closed
+		if _, ok := <-sub.C(); ok {
+			t.Errorf("subscriber %d: channel still open after Close", i)
+		}
+	}
+	if n := hub.Subscribers(); n != 0 {
+		t.Errorf("Subscribers() = %d after Close", n)
+	}
+	if err := hub.Publish("n", 3); !errors.Is(err, ErrClosed) {
+		t.Errorf("Publish after Close = %v, want ErrClosed", err)
+	}
+	if _, err := hub.Subscribe(1, Block); !errors.Is(err, ErrClosed) {
+		t.Errorf("Subscribe after Close = %v, want ErrClosed", err)
+	}
+}
+
+// Examples run one at a time: leakcheck compares goroutine snapshots, so a
+// parallel test would look like a leak.
+func TestExamplesDoNotLeakOrDeadlock(t *testing.T) {
+	leakcheck.Check(t,
+		leakcheck.Example{Name: "everySubscriberGetsEveryMessage", Fn: everySubscriberGetsEveryMessage},
+		leakcheck.Example{Name: "topicFiltering", Fn: topicFiltering},
+		leakcheck.Example{Name: "slowConsumerPolicies", Fn: slowConsumerPolicies},
+		leakcheck.Example{Name: "blockPolicy", Fn: blockPolicy},
+	)
+}
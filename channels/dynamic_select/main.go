@@ -0,0 +1,330 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// --- 1. Cases and Results ---
+
+// CaseID identifies a case added to a Selector.
+type CaseID int
+
+// Dir tells whether a case receives or sends.
+type Dir int
+
+const (
+	Recv Dir = iota
+	Send
+)
+
+func (d Dir) String() string {
+	if d == Send {
+		return "send"
+	}
+	return "recv"
+}
+
+// Result describes the case that fired.
+type Result[T any] struct {
+	ID     CaseID
+	Dir    Dir
+	Value  T    // The received value (zero for sends and closed channels)
+	Closed bool // A receive case found its channel closed and was removed
+}
+
+// ErrNoCases is returned by Select when there is nothing to wait on.
+var ErrNoCases = errors.New("selector: no cases")
+
+type selectorCase struct {
+	id  CaseID
+	dir Dir
+	sc  reflect.SelectCase
+}
+
+// --- 2. The Selector ---
+
+// Selector is a select statement whose cases can change at runtime.
+// A normal select has a fixed set of cases written in the source; this one
+// builds a []reflect.SelectCase from whatever is registered right now.
+//
+// Cases may be added and removed from any goroutine, including while
+// another goroutine is blocked in Select. Select itself has a single
+// consumer: only one goroutine may call it at a time.
+type Selector[T any] struct {
+	mu     sync.Mutex
+	cases  []selectorCase
+	nextID CaseID
+
+	// wake interrupts the blocked Select so it picks up new or removed
+	// cases. One buffered slot is enough because there is one consumer.
+	wake chan struct{}
+
+	// Remove hands off with an in-flight Select through these, see Remove
+	selecting bool       // Select is inside reflect.Select
+	removing  int        // Removes waiting for it to come out
+	idle      *sync.Cond // Broadcast when either of the above changes
+}
+
+// NewSelector creates an empty Selector.
+func NewSelector[T any]() *Selector[T] {
+	s := &Selector[T]{wake: make(chan struct{}, 1)}
+	s.idle = sync.NewCond(&s.mu)
+	return s
+}
+
+// AddRecv adds a case that receives from ch.
+func (s *Selector[T]) AddRecv(ch <-chan T) CaseID {
+	return s.add(Recv, reflect.SelectCase{
+		Dir:  reflect.SelectRecv,
+		Chan: reflect.ValueOf(ch),
+	})
+}
+
+// AddSend adds a case that sends v on ch. Send cases fire once:
+// after the value is delivered the case is removed.
+func (s *Selector[T]) AddSend(ch chan<- T, v T) CaseID {
+	return s.add(Send, reflect.SelectCase{
+		Dir:  reflect.SelectSend,
+		Chan: reflect.ValueOf(ch),
+		Send: reflect.ValueOf(&v).Elem(), // Works for interface T too, unlike ValueOf(v)
+	})
+}
+
+func (s *Selector[T]) add(dir Dir, sc reflect.SelectCase) CaseID {
+	s.mu.Lock()
+	id := s.nextID
+	s.nextID++
+	s.cases = append(s.cases, selectorCase{id: id, dir: dir, sc: sc})
+	s.mu.Unlock()
+
+	s.notify()
+	return id
+}
+
+// Remove deletes a case. It reports whether the case was still registered.
+//
+// The case may be part of a reflect.Select that is already blocked, and
+// that select cannot be edited. So Remove wakes it and waits until it has
+// returned: if the case fired in the meantime, that Select reports it (and
+// a fired send case is gone, so Remove returns false). Once Remove
+// returns, the case can never fire again.
+func (s *Selector[T]) Remove(id CaseID) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.removing++
+	for s.selecting {
+		s.notify()
+		s.idle.Wait()
+	}
+	removed := s.removeLocked(id)
+	s.removing--
+	s.idle.Broadcast()
+
+	return removed
+}
+
+func (s *Selector[T]) removeLocked(id CaseID) bool {
+	for i, c := range s.cases {
+		if c.id == id {
+			s.cases = append(s.cases[:i], s.cases[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Selector[T]) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default: // A wake-up is already pending
+	}
+}
+
+// Len returns the number of registered cases.
+func (s *Selector[T]) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.cases)
+}
+
+// Select blocks until a case fires or ctx is done.
+// A receive from a closed channel removes that case automatically, just
+// like setting the channel to nil does in nilChannelBehavior.
+func (s *Selector[T]) Select(ctx context.Context) (Result[T], error) {
+	for {
+		s.mu.Lock()
+		for s.removing > 0 {
+			s.idle.Wait() // Let pending Removes go first
+		}
+		if len(s.cases) == 0 {
+			s.mu.Unlock()
+			return Result[T]{}, ErrNoCases
+		}
+
+		// Two fixed cases first: ctx and the wake-up channel
+		cases := make([]reflect.SelectCase, 2, len(s.cases)+2)
+		cases[0] = reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())}
+		cases[1] = reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(s.wake)}
+		snapshot := make([]selectorCase, len(s.cases))
+		copy(snapshot, s.cases)
+		for _, c := range snapshot {
+			cases = append(cases, c.sc)
+		}
+		s.selecting = true
+		s.mu.Unlock()
+
+		chosen, recv, recvOK := reflect.Select(cases)
+
+		// Back under the lock before anything else: a Remove waiting for
+		// this select must see the outcome, including a send case that
+		// has just fired and is no longer registered
+		s.mu.Lock()
+		s.selecting = false
+		s.idle.Broadcast()
+
+		switch chosen {
+		case 0:
+			s.mu.Unlock()
+			return Result[T]{}, ctx.Err()
+		case 1:
+			s.mu.Unlock()
+			continue // Cases changed, rebuild
+		}
+
+		c := snapshot[chosen-2]
+		res := Result[T]{ID: c.id, Dir: c.dir}
+
+		switch {
+		case c.dir == Send:
+			s.removeLocked(c.id)
+		case !recvOK:
+			res.Closed = true
+			s.removeLocked(c.id)
+		default:
+			res.Value, _ = recv.Interface().(T) // The ", _" keeps a nil interface value from panicking
+		}
+		s.mu.Unlock()
+		return res, nil
+	}
+}
+
+// --- 3. Examples ---
+
+func changingPeers() {
+	fmt.Println("\n=== 1. ADDING AND REMOVING PEERS AT RUNTIME ===")
+
+	sel := NewSelector[string]()
+
+	// peer sends n messages and then disconnects (closes its channel)
+	peer := func(name string, n int, delay time.Duration) <-chan string {
+		ch := make(chan string)
+		go func() {
+			defer close(ch)
+			for i := 1; i <= n; i++ {
+				time.Sleep(delay)
+				ch <- fmt.Sprintf("%s: message %d", name, i)
+			}
+		}()
+		return ch
+	}
+
+	sel.AddRecv(peer("alice", 2, 30*time.Millisecond))
+	sel.AddRecv(peer("bob", 3, 50*time.Millisecond))
+
+	// carol connects later, while the loop is already blocked in Select
+	go func() {
+		time.Sleep(60 * time.Millisecond)
+		sel.AddRecv(peer("carol", 1, 10*time.Millisecond))
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	for {
+		res, err := sel.Select(ctx)
+		if errors.Is(err, ErrNoCases) {
+			fmt.Println("All peers disconnected")
+			return
+		}
+		if err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
+
+		if res.Closed {
+			fmt.Printf("Peer #%d disconnected, %d case(s) left\n", res.ID, sel.Len())
+			continue
+		}
+		fmt.Printf("Case #%d fired: %s\n", res.ID, res.Value)
+	}
+}
+
+func mixedSendAndRecv() {
+	fmt.Println("\n=== 2. MIXED SEND AND RECEIVE CASES ===")
+
+	sel := NewSelector[int]()
+
+	outbox := make(chan int, 1)
+	inbox := make(chan int, 1)
+	inbox <- 42
+
+	sel.AddSend(outbox, 7)
+	sel.AddRecv(inbox)
+
+	// Both cases are ready; each fires once and reports its direction
+	for i := 0; i < 2; i++ {
+		res, err := sel.Select(context.Background())
+		if err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
+		if res.Dir == Send {
+			fmt.Printf("Case #%d: sent to outbox (now holds %d)\n", res.ID, <-outbox)
+		} else {
+			fmt.Printf("Case #%d: received %d from inbox\n", res.ID, res.Value)
+		}
+	}
+
+	fmt.Println("Remaining cases:", sel.Len())
+}
+
+func removeCase() {
+	fmt.Println("\n=== 3. DISABLING A CASE WITH REMOVE ===")
+
+	sel := NewSelector[int]()
+
+	ch1 := make(chan int, 1)
+	ch2 := make(chan int, 1)
+	ch1 <- 1
+	ch2 <- 2
+
+	id1 := sel.AddRecv(ch1)
+	sel.AddRecv(ch2)
+
+	// Same effect as `ch1 = nil` in nilChannelBehavior
+	sel.Remove(id1)
+
+	res, _ := sel.Select(context.Background())
+	fmt.Printf("From case #%d: %d\n", res.ID, res.Value)
+}
+
+func main() {
+	fmt.Println("🎛️ GO DYNAMIC SELECT - COMPLETE GUIDE")
+	fmt.Println("=====================================")
+
+	changingPeers()
+	time.Sleep(300 * time.Millisecond)
+
+	mixedSendAndRecv()
+	time.Sleep(300 * time.Millisecond)
+
+	removeCase()
+
+	fmt.Println("\n✅ All examples completed!")
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"sugar/channels/leakcheck"
+)
+
+// A send case that has delivered its value is spent: Remove must not
+// report it as removed, even while the Select that fired it has not yet
+// returned.
+func TestRemoveAfterSendFired(t *testing.T) {
+	for range 200 {
+		sel := NewSelector[int]()
+		ch := make(chan int)
+		id := sel.AddSend(ch, 1)
+		sel.AddRecv(make(chan int)) // Keeps Select blocked once the send case is gone
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			sel.Select(ctx)
+		}()
+
+		time.Sleep(time.Millisecond) // Let Select block first, so this goroutine runs on after the receive
+		<-ch
+		if sel.Remove(id) {
+			t.Fatal("Remove() = true for a send case that already fired")
+		}
+		cancel()
+		<-done
+	}
+}
+
+func TestRemovedCaseNeverFires(t *testing.T) {
+	sel := NewSelector[int]()
+	ch := make(chan int, 1)
+	id := sel.AddRecv(ch)
+	sel.AddRecv(make(chan int))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	errs := make(chan error, 1)
+	go func() {
+		_, err := sel.Select(ctx)
+		errs <- err
+	}()
+
+	time.Sleep(10 * time.Millisecond) // Let Select block
+	if !sel.Remove(id) {
+		t.Fatal("Remove() = false for a registered case")
+	}
+	ch <- 1
+
+	if err := <-errs; err != context.DeadlineExceeded {
+		t.Fatalf("Select() = %v, want the deadline: the removed case fired", err)
+	}
+	if len(ch) != 1 {
+		t.Fatal("the value sent after Remove was taken")
+	}
+}
+
+// Examples run one at a time: leakcheck compares goroutine snapshots, so a
+// parallel test would look like a leak.
+func TestExamplesDoNotLeakOrDeadlock(t *testing.T) {
+	leakcheck.Check(t,
+		leakcheck.Example{Name: "changingPeers", Fn: changingPeers},
+		leakcheck.Example{Name: "mixedSendAndRecv", Fn: mixedSendAndRecv},
+		leakcheck.Example{Name: "removeCase", Fn: removeCase},
+	)
+}
@@ -0,0 +1,201 @@
+// Package leakcheck finds goroutines a test leaves behind and channel
+// operations that block forever. Use it from a _test.go file:
+//
+//	func TestExample(t *testing.T) {
+//		snap := leakcheck.TakeSnapshot()
+//		leakcheck.WithDeadlockTimeout(t, 5*time.Second, example)
+//		leakcheck.VerifyNoLeaks(t, snap, time.Second)
+//	}
+//
+// Check does the same for a whole list of examples, one subtest each.
+//
+// Tests that use it must not call t.Parallel: goroutines of a concurrent
+// test would look like leaks.
+package leakcheck
+
+import (
+	"bytes"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// --- 1. Goroutine Snapshots ---
+
+// goroutine is one entry of a runtime.Stack dump.
+type goroutine struct {
+	id    int
+	stack string
+}
+
+// Snapshot records which goroutines exist at a point in time.
+type Snapshot map[int]bool
+
+// dumpGoroutines returns the stacks of every goroutine in the process.
+func dumpGoroutines() []byte {
+	buf := make([]byte, 64<<10)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			return buf[:n]
+		}
+		buf = make([]byte, 2*len(buf)) // Dump was truncated, try again
+	}
+}
+
+// parseGoroutines splits a dump into goroutines. Each block starts with
+// a header like "goroutine 7 [chan send]:".
+func parseGoroutines(dump []byte) []goroutine {
+	var out []goroutine
+	for _, block := range bytes.Split(dump, []byte("\n\n")) {
+		header, _, _ := strings.Cut(string(block), "\n")
+		fields := strings.Fields(header)
+		if len(fields) < 2 || fields[0] != "goroutine" {
+			continue
+		}
+		id, err := strconv.Atoi(fields[1])
+		if err != nil {
+			continue
+		}
+		out = append(out, goroutine{id: id, stack: string(block)})
+	}
+	return out
+}
+
+// TakeSnapshot remembers the goroutines that are running right now.
+// Call it at the start of a test, before anything is spawned.
+func TakeSnapshot() Snapshot {
+	snap := make(Snapshot)
+	for _, g := range parseGoroutines(dumpGoroutines()) {
+		snap[g.id] = true
+	}
+	return snap
+}
+
+// leakedSince returns goroutines that were not in the snapshot,
+// ignoring the calling goroutine and the ones opts tell it to.
+func leakedSince(snap Snapshot, opts []Option) []goroutine {
+	all := parseGoroutines(dumpGoroutines())
+
+	var leaked []goroutine
+	for i, g := range all {
+		// runtime.Stack always lists the current goroutine first
+		if i == 0 || snap[g.id] || ignored(g, opts) {
+			continue
+		}
+		leaked = append(leaked, g)
+	}
+	return leaked
+}
+
+// Option makes VerifyNoLeaks accept a goroutine it would report. It is
+// given the goroutine's stack as printed by runtime.Stack.
+type Option func(stack string) bool
+
+// IgnoreFunction accepts goroutines running fn or a closure inside it, for
+// a goroutine an example leaves blocked on purpose. Name fn the way a stack
+// trace does, import path included - for a command that is its directory:
+//
+//	leakcheck.IgnoreFunction("sugar/channels/advanced.basicSelect")
+func IgnoreFunction(fn string) Option {
+	return func(stack string) bool {
+		for _, line := range strings.Split(stack, "\n")[1:] {
+			if strings.HasPrefix(line, "\t") || strings.HasPrefix(line, "created by ") {
+				continue // File positions and the spawning frame are not running code
+			}
+			name, _, _ := strings.Cut(line, "(")
+			if name == fn || strings.HasPrefix(name, fn+".") {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func ignored(g goroutine, opts []Option) bool {
+	for _, ignore := range opts {
+		if ignore(g.stack) {
+			return true
+		}
+	}
+	return false
+}
+
+// --- 2. Test Helpers ---
+
+// TB is the subset of testing.TB the helpers need. *testing.T satisfies
+// it; the package's own tests pass a recorder instead.
+type TB interface {
+	Helper()
+	Errorf(format string, args ...any)
+}
+
+// VerifyNoLeaks fails t with the stacks of any goroutine started after snap
+// that is still alive once grace has passed. The grace period gives
+// goroutines that are already finishing time to exit.
+func VerifyNoLeaks(t TB, snap Snapshot, grace time.Duration, opts ...Option) {
+	t.Helper()
+
+	deadline := time.Now().Add(grace)
+	for {
+		leaked := leakedSince(snap, opts)
+		if len(leaked) == 0 {
+			return
+		}
+		if time.Now().After(deadline) {
+			stacks := make([]string, len(leaked))
+			for i, g := range leaked {
+				stacks[i] = g.stack
+			}
+			t.Errorf("found %d leaked goroutine(s):\n\n%s", len(leaked), strings.Join(stacks, "\n\n"))
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// WithDeadlockTimeout runs fn and fails t with a dump of every goroutine
+// if fn has not returned within limit. A blocked channel operation shows
+// up in the dump as "[chan send]" or "[chan receive]".
+func WithDeadlockTimeout(t TB, limit time.Duration, fn func()) {
+	t.Helper()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		fn()
+	}()
+
+	timer := time.NewTimer(limit)
+	defer timer.Stop()
+
+	select {
+	case <-done:
+	case <-timer.C:
+		t.Errorf("blocked for more than %s, all goroutines:\n\n%s", limit, dumpGoroutines())
+	}
+}
+
+// --- 3. Example Suites ---
+
+// Example is one example function of a guide.
+type Example struct {
+	Name string
+	Fn   func()
+	Opts []Option // Leaks the example shows on purpose
+}
+
+// Check runs each example as a subtest and fails it if the example blocks
+// for more than 5 seconds or leaves goroutines behind.
+func Check(t *testing.T, examples ...Example) {
+	t.Helper()
+	for _, ex := range examples {
+		t.Run(ex.Name, func(t *testing.T) {
+			snap := TakeSnapshot()
+			WithDeadlockTimeout(t, 5*time.Second, ex.Fn)
+			VerifyNoLeaks(t, snap, time.Second, ex.Opts...)
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package leakcheck
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// recorder is a TB that keeps failures instead of failing the test, so the
+// helpers can be checked against code that is supposed to fail.
+type recorder struct {
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestVerifyNoLeaksReportsBlockedGoroutine(t *testing.T) {
+	snap := TakeSnapshot()
+	release := make(chan struct{})
+	defer close(release)
+
+	// The reader walks away after one value, like a fan-in whose consumer
+	// stops early: the second forwarder blocks on its send forever.
+	out := make(chan int)
+	for i := range 2 {
+		go func() {
+			select {
+			case out <- i:
+			case <-release:
+			}
+		}()
+	}
+	<-out
+
+	r := &recorder{}
+	VerifyNoLeaks(r, snap, 50*time.Millisecond)
+	if len(r.errors) != 1 || !strings.Contains(r.errors[0], "found 1 leaked goroutine") {
+		t.Fatalf("errors = %q, want one leaked goroutine", r.errors)
+	}
+	if !strings.Contains(r.errors[0], "[select]") {
+		t.Errorf("report does not include the blocked stack: %s", r.errors[0])
+	}
+}
+
+func TestVerifyNoLeaksWaitsForGrace(t *testing.T) {
+	snap := TakeSnapshot()
+	go time.Sleep(20 * time.Millisecond) // Finishing, not leaked
+
+	r := &recorder{}
+	VerifyNoLeaks(r, snap, time.Second)
+	if len(r.errors) != 0 {
+		t.Fatalf("errors = %q, want none", r.errors)
+	}
+}
+
+func TestWithDeadlockTimeoutDumpsBlockedReceive(t *testing.T) {
+	var ch chan string // Receiving from a nil channel blocks forever
+	release := make(chan struct{})
+	defer close(release)
+
+	r := &recorder{}
+	WithDeadlockTimeout(r, 50*time.Millisecond, func() {
+		select {
+		case <-ch:
+		case <-release:
+		}
+	})
+	if len(r.errors) != 1 || !strings.Contains(r.errors[0], "blocked for more than 50ms") {
+		t.Fatalf("errors = %q, want a deadlock report", r.errors)
+	}
+}
+
+func TestWithDeadlockTimeoutPassesWhenFnReturns(t *testing.T) {
+	r := &recorder{}
+	WithDeadlockTimeout(r, time.Second, func() {
+		ch := make(chan int, 1)
+		ch <- 1
+		<-ch
+	})
+	if len(r.errors) != 0 {
+		t.Fatalf("errors = %q, want none", r.errors)
+	}
+}
+
+func blockedForever(release <-chan struct{}) { <-release }
+
+func TestIgnoreFunctionSkipsNamedGoroutine(t *testing.T) {
+	snap := TakeSnapshot()
+	release := make(chan struct{})
+	defer close(release)
+
+	go blockedForever(release)
+	go func() { <-release }()
+
+	r := &recorder{}
+	VerifyNoLeaks(r, snap, 50*time.Millisecond, IgnoreFunction("sugar/channels/leakcheck.blockedForever"))
+	if len(r.errors) != 1 || !strings.Contains(r.errors[0], "found 1 leaked goroutine") {
+		t.Fatalf("errors = %q, want only the unnamed goroutine", r.errors)
+	}
+	if strings.Contains(r.errors[0], "blockedForever") {
+		t.Errorf("ignored goroutine was reported: %s", r.errors[0])
+	}
+}
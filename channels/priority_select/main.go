@@ -0,0 +1,290 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// --- 1. Priorities ---
+
+// Priority is the level a value was received on.
+type Priority int
+
+const (
+	High Priority = iota
+	Normal
+	Low
+	numPriorities
+)
+
+func (p Priority) String() string {
+	switch p {
+	case High:
+		return "high"
+	case Normal:
+		return "normal"
+	case Low:
+		return "low"
+	}
+	return fmt.Sprintf("Priority(%d)", int(p))
+}
+
+// --- 2. PrioritySelect: One Prioritized Receive ---
+
+// PrioritySelect receives one value, always preferring high over normal
+// over low. A plain select picks randomly when several cases are ready
+// (see selectMultipleReady); this checks each level in order first and
+// only blocks on all of them when none is ready.
+//
+// Nil channels are skipped. ok is false if every channel is nil, or if
+// ctx is done before anything arrives. A closed channel is reported with
+// closed set, so the caller can nil it out.
+func PrioritySelect[T any](ctx context.Context, high, normal, low <-chan T) (v T, from Priority, closed, ok bool) {
+	// Pass 1: non-blocking, in priority order
+	if v, from, closed, ok = tryPriority(high, normal, low); ok {
+		return v, from, closed, true
+	}
+
+	if high == nil && normal == nil && low == nil {
+		return v, 0, false, false
+	}
+
+	// Pass 2: nothing was ready, so whichever arrives first is the
+	// highest-priority value available
+	select {
+	case v, open := <-high:
+		return v, High, !open, true
+	case v, open := <-normal:
+		return v, Normal, !open, true
+	case v, open := <-low:
+		return v, Low, !open, true
+	case <-ctx.Done():
+		return v, 0, false, false
+	}
+}
+
+// tryPriority is the non-blocking pass of PrioritySelect. ok is false when
+// no channel is ready right now.
+func tryPriority[T any](high, normal, low <-chan T) (v T, from Priority, closed, ok bool) {
+	for p, ch := range [numPriorities]<-chan T{high, normal, low} {
+		if ch == nil {
+			continue
+		}
+		select {
+		case v, open := <-ch:
+			return v, Priority(p), !open, true
+		default:
+		}
+	}
+	return v, 0, false, false
+}
+
+// --- 3. PriorityMerge: A Prioritized Stream ---
+
+// Weights is the starvation guard for WeightedPriorityMerge. While every
+// level has a backlog, each round delivers up to High values from high,
+// then Normal from normal, then Low from low.
+type Weights struct {
+	High, Normal, Low int
+}
+
+// PriorityMerge merges three channels into one, strictly draining higher
+// priorities first. Low values only get through when nothing else is waiting,
+// so a busy high channel can starve them - see WeightedPriorityMerge.
+// The output closes when all inputs are closed or ctx is done.
+func PriorityMerge[T any](ctx context.Context, high, normal, low <-chan T) <-chan T {
+	return merge(ctx, nil, high, normal, low)
+}
+
+// WeightedPriorityMerge is PriorityMerge with a fair-share guard,
+// so lower priorities keep making progress under load.
+func WeightedPriorityMerge[T any](ctx context.Context, w Weights, high, normal, low <-chan T) <-chan T {
+	return merge(ctx, &w, high, normal, low)
+}
+
+func merge[T any](ctx context.Context, w *Weights, high, normal, low <-chan T) <-chan T {
+	out := make(chan T)
+
+	go func() {
+		defer close(out)
+
+		var credits, weights [numPriorities]int
+		if w != nil {
+			weights = [numPriorities]int{w.High, w.Normal, w.Low}
+			credits = weights
+		}
+
+		// take spends one credit of p, starting a new round when p is out of
+		// credit or every level has used its share.
+		take := func(p Priority) {
+			if w == nil {
+				return
+			}
+			if credits[p] <= 0 {
+				credits = weights
+			}
+			credits[p]--
+			if credits == [numPriorities]int{} {
+				credits = weights
+			}
+		}
+
+		for high != nil || normal != nil || low != nil {
+			var v T
+			var from Priority
+			var closed bool
+
+			// Levels that still have credit this round go first. If none of
+			// them has anything ready, any level may go (and starts a new round).
+			ok := false
+			if w != nil {
+				v, from, closed, ok = tryPriority(
+					withCredit(high, credits[High]),
+					withCredit(normal, credits[Normal]),
+					withCredit(low, credits[Low]),
+				)
+			}
+			if !ok {
+				if v, from, closed, ok = PrioritySelect(ctx, high, normal, low); !ok {
+					return // Cancelled while waiting for input
+				}
+			}
+
+			if closed {
+				switch from {
+				case High:
+					high = nil
+				case Normal:
+					normal = nil
+				case Low:
+					low = nil
+				}
+				continue
+			}
+
+			take(from)
+			select {
+			case out <- v:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out
+}
+
+// withCredit disables a channel (the nil-channel trick) once its level has
+// used up its share of the current round.
+func withCredit[T any](ch <-chan T, credit int) <-chan T {
+	if credit <= 0 {
+		return nil
+	}
+	return ch
+}
+
+// --- 4. Examples ---
+
+// collect drains out and joins the values, so a run can be compared
+// against a literal (see main_test.go).
+func collect(out <-chan string) string {
+	var got []string
+	for v := range out {
+		got = append(got, v)
+	}
+	return strings.Join(got, " ")
+}
+
+// fill returns a closed, buffered channel holding the given values.
+// Pre-filled channels make every run produce the same order.
+func fill(prefix string, n int) <-chan string {
+	ch := make(chan string, n)
+	for i := 1; i <= n; i++ {
+		ch <- fmt.Sprintf("%s%d", prefix, i)
+	}
+	close(ch)
+	return ch
+}
+
+func singlePrioritySelect() {
+	fmt.Println("\n=== 1. PRIORITY SELECT WITH ALL CHANNELS READY ===")
+
+	high := make(chan string, 1)
+	normal := make(chan string, 1)
+	low := make(chan string, 1)
+
+	low <- "L1"
+	normal <- "N1"
+	high <- "H1"
+
+	// A plain select would pick randomly here
+	for i := 0; i < 3; i++ {
+		v, from, _, _ := PrioritySelect(context.Background(), high, normal, low)
+		fmt.Printf("Selected %s from %s\n", v, from)
+	}
+}
+
+func strictMerge() {
+	fmt.Println("\n=== 2. STRICT PRIORITY MERGE ===")
+
+	out := PriorityMerge(context.Background(), fill("H", 3), fill("N", 3), fill("L", 3))
+	fmt.Println("Order:", collect(out))
+}
+
+func weightedMerge() {
+	fmt.Println("\n=== 3. WEIGHTED MERGE (STARVATION GUARD) ===")
+
+	w := Weights{High: 4, Normal: 2, Low: 1}
+	out := WeightedPriorityMerge(context.Background(), w, fill("H", 10), fill("N", 5), fill("L", 3))
+
+	// Low gets one slot in every round of 4+2+1, even with high backlogged
+	fmt.Printf("Weights %+v\n", w)
+	fmt.Println("Order:", collect(out))
+}
+
+func controlOvertakesBulk() {
+	fmt.Println("\n=== 4. CONTROL MESSAGES OVERTAKE BULK DATA ===")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	control := make(chan string)
+	bulk := make(chan string, 100)
+	for i := 1; i <= 100; i++ {
+		bulk <- fmt.Sprintf("chunk-%d", i)
+	}
+
+	out := PriorityMerge(ctx, control, nil, bulk)
+
+	for i := 1; i <= 3; i++ {
+		fmt.Println("Received:", <-out)
+	}
+
+	go func() { control <- "SHUTDOWN" }()
+	time.Sleep(10 * time.Millisecond)
+
+	// At most one bulk chunk was already in flight in the merge goroutine
+	for i := 0; i < 2; i++ {
+		fmt.Println("Received:", <-out)
+	}
+}
+
+func main() {
+	fmt.Println("🥇 GO PRIORITY SELECT - COMPLETE GUIDE")
+	fmt.Println("======================================")
+
+	singlePrioritySelect()
+	time.Sleep(300 * time.Millisecond)
+
+	strictMerge()
+	time.Sleep(300 * time.Millisecond)
+
+	weightedMerge()
+	time.Sleep(300 * time.Millisecond)
+
+	controlOvertakesBulk()
+
+	fmt.Println("\n✅ All examples completed!")
+}
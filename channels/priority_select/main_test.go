@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"sugar/channels/leakcheck"
+)
+
+// With pre-filled inputs the merge order is fixed, so it can be compared
+// against a literal.
+func TestMergeOrder(t *testing.T) {
+	tests := []struct {
+		name string
+		out  func() <-chan string
+		want string
+	}{
+		{
+			name: "strict",
+			out: func() <-chan string {
+				return PriorityMerge(context.Background(), fill("H", 3), fill("N", 3), fill("L", 3))
+			},
+			want: "H1 H2 H3 N1 N2 N3 L1 L2 L3",
+		},
+		{
+			name: "weighted",
+			out: func() <-chan string {
+				w := Weights{High: 4, Normal: 2, Low: 1}
+				return WeightedPriorityMerge(context.Background(), w, fill("H", 10), fill("N", 5), fill("L", 3))
+			},
+			// Low gets one slot in every round of 4+2+1, even with high backlogged
+			want: "H1 H2 H3 H4 N1 N2 L1 H5 H6 H7 H8 N3 N4 L2 H9 H10 N5 L3",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := collect(tt.out()); got != tt.want {
+				t.Errorf("order = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrioritySelectPrefersHigh(t *testing.T) {
+	high := make(chan int, 1)
+	normal := make(chan int, 1)
+	low := make(chan int, 1)
+	low <- 3
+	normal <- 2
+	high <- 1
+
+	for _, want := range []Priority{High, Normal, Low} {
+		if _, from, _, _ := PrioritySelect(context.Background(), high, normal, low); from != want {
+			t.Fatalf("selected %s, want %s", from, want)
+		}
+	}
+}
+
+func TestPrioritySelectCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	never := make(chan int)
+	if _, _, _, ok := PrioritySelect(ctx, never, never, never); ok {
+		t.Fatal("PrioritySelect on a cancelled ctx returned ok")
+	}
+}
+
+// A merge waiting on idle inputs must close its output when ctx is done.
+func TestMergeStopsOnCancel(t *testing.T) {
+	snap := leakcheck.TakeSnapshot()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	idle := make(chan string)
+	out := PriorityMerge(ctx, idle, idle, idle)
+	cancel()
+
+	leakcheck.WithDeadlockTimeout(t, time.Second, func() {
+		for range out {
+		}
+	})
+	leakcheck.VerifyNoLeaks(t, snap, time.Second)
+}
+
+// Examples run one at a time: leakcheck compares goroutine snapshots, so a
+// parallel test would look like a leak.
+func TestExamplesDoNotLeakOrDeadlock(t *testing.T) {
+	leakcheck.Check(t,
+		leakcheck.Example{Name: "singlePrioritySelect", Fn: singlePrioritySelect},
+		leakcheck.Example{Name: "strictMerge", Fn: strictMerge},
+		leakcheck.Example{Name: "weightedMerge", Fn: weightedMerge},
+		leakcheck.Example{Name: "controlOvertakesBulk", Fn: controlOvertakesBulk},
+	)
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"sugar/clock"
+)
+
+// --- 1. The Rate Limiter ---
+
+// RateLimiter hands out one token per interval, with bursts up to burst.
+// It refills on an injected clock, so tests don't wait for real seconds.
+type RateLimiter struct {
+	tokens   chan struct{}
+	ticker   clock.Ticker
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewRateLimiter starts a limiter that begins with a full bucket.
+func NewRateLimiter(clk clock.Clock, interval time.Duration, burst int) *RateLimiter {
+	rl := &RateLimiter{
+		tokens: make(chan struct{}, burst),
+		ticker: clk.NewTicker(interval),
+		done:   make(chan struct{}),
+	}
+	for i := 0; i < burst; i++ {
+		rl.tokens <- struct{}{}
+	}
+
+	go func() {
+		for {
+			select {
+			case <-rl.ticker.C():
+				select {
+				case rl.tokens <- struct{}{}:
+				default: // Bucket is full
+				}
+			case <-rl.done:
+				return
+			}
+		}
+	}()
+	return rl
+}
+
+// Wait blocks until a token is available.
+func (rl *RateLimiter) Wait() { <-rl.tokens }
+
+// Allow takes a token if one is available, without blocking.
+func (rl *RateLimiter) Allow() bool {
+	select {
+	case <-rl.tokens:
+		return true
+	default:
+		return false
+	}
+}
+
+// Stop ends the refill goroutine. Calling it more than once is safe.
+func (rl *RateLimiter) Stop() {
+	rl.stopOnce.Do(func() {
+		rl.ticker.Stop()
+		close(rl.done)
+	})
+}
+
+// --- 2. Examples ---
+
+func rateLimitWithFakeClock() {
+	fmt.Println("\n=== 1. RATE LIMITER ON A FAKE CLOCK ===")
+
+	clk := clock.NewFakeClock(time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC))
+	rl := NewRateLimiter(clk, time.Second, 2)
+	defer rl.Stop()
+
+	// The burst of 2 is available immediately
+	for i := 1; i <= 3; i++ {
+		fmt.Printf("Request %d allowed: %t\n", i, rl.Allow())
+	}
+
+	clk.Advance(time.Second)
+	rl.Wait() // The refill goroutine needs a moment to hand over the new token
+	fmt.Println("After 1s: one more request allowed")
+}
+
+func rateLimitOnRealClock() {
+	fmt.Println("\n=== 2. THE SAME LIMITER ON THE REAL CLOCK ===")
+
+	rl := NewRateLimiter(clock.Real, 100*time.Millisecond, 1)
+	defer rl.Stop()
+
+	start := time.Now()
+	for i := 1; i <= 3; i++ {
+		rl.Wait()
+		fmt.Printf("Request %d at %s\n", i, time.Since(start).Round(50*time.Millisecond))
+	}
+}
+
+func main() {
+	fmt.Println("🚰 GO RATE LIMITER - COMPLETE GUIDE")
+	fmt.Println("===================================")
+
+	rateLimitWithFakeClock()
+	time.Sleep(300 * time.Millisecond)
+
+	rateLimitOnRealClock()
+
+	fmt.Println("\n✅ All examples completed!")
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"sugar/clock"
+
+	"sugar/channels/leakcheck"
+)
+
+func TestRefillOnFakeClock(t *testing.T) {
+	clk := clock.NewFakeClock(time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC))
+	rl := NewRateLimiter(clk, time.Second, 1)
+	defer rl.Stop()
+
+	if !rl.Allow() {
+		t.Fatal("Allow() = false with a full bucket")
+	}
+	if rl.Allow() {
+		t.Fatal("Allow() = true with an empty bucket")
+	}
+
+	clk.Advance(time.Second)
+	done := make(chan struct{})
+	go func() {
+		rl.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("no token after the clock moved one interval")
+	}
+}
+
+func TestStopTwice(t *testing.T) {
+	rl := NewRateLimiter(clock.Real, time.Second, 1)
+	rl.Stop()
+	rl.Stop() // Must not panic on the closed done channel
+}
+
+// Examples run one at a time: leakcheck compares goroutine snapshots, so a
+// parallel test would look like a leak.
+func TestExamplesDoNotLeakOrDeadlock(t *testing.T) {
+	leakcheck.Check(t,
+		leakcheck.Example{Name: "rateLimitWithFakeClock", Fn: rateLimitWithFakeClock},
+		leakcheck.Example{Name: "rateLimitOnRealClock", Fn: rateLimitOnRealClock},
+	)
+}
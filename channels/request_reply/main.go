@@ -0,0 +1,297 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"sugar/panics"
+)
+
+// --- 1. The Envelope ---
+
+// Envelope is one in-flight request. It carries its own reply channel,
+// so many callers can share a single request channel.
+type Envelope[Req, Resp any] struct {
+	ID  uint64          // Correlation ID, unique per Call
+	Ctx context.Context // The caller's context - handlers should respect it
+	Req Req
+
+	reply chan result[Resp]
+}
+
+type result[Resp any] struct {
+	resp Resp
+	err  error
+}
+
+// Reply sends the response back to the caller. It never blocks, even if
+// the caller has already given up, because the reply channel has room for one value.
+func (e *Envelope[Req, Resp]) Reply(resp Resp, err error) {
+	select {
+	case e.reply <- result[Resp]{resp: resp, err: err}:
+	default:
+		// Already replied - extra replies are dropped
+	}
+}
+
+// --- 2. The Call Primitive ---
+
+// ErrClosed is returned by Request after the Call has been closed.
+var ErrClosed = errors.New("call: closed")
+
+// Call is a typed request/reply channel. Clients use Request; servers
+// receive from Requests() or use Serve.
+type Call[Req, Resp any] struct {
+	requests chan *Envelope[Req, Resp]
+	nextID   atomic.Uint64
+
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+// NewCall creates a Call whose request channel buffers up to buf envelopes.
+func NewCall[Req, Resp any](buf int) *Call[Req, Resp] {
+	return &Call[Req, Resp]{
+		requests: make(chan *Envelope[Req, Resp], buf),
+		closed:   make(chan struct{}),
+	}
+}
+
+// Request sends req and waits for the reply, ctx cancellation or Close.
+func (c *Call[Req, Resp]) Request(ctx context.Context, req Req) (Resp, error) {
+	var zero Resp
+
+	env := &Envelope[Req, Resp]{
+		ID:    c.nextID.Add(1),
+		Ctx:   ctx,
+		Req:   req,
+		reply: make(chan result[Resp], 1),
+	}
+
+	select {
+	case c.requests <- env:
+	case <-ctx.Done():
+		return zero, fmt.Errorf("request %d not sent: %w", env.ID, ctx.Err())
+	case <-c.closed:
+		return zero, ErrClosed
+	}
+
+	select {
+	case r := <-env.reply:
+		return r.resp, r.err
+	case <-ctx.Done():
+		return zero, fmt.Errorf("request %d: no reply: %w", env.ID, ctx.Err())
+	case <-c.closed:
+		return zero, ErrClosed
+	}
+}
+
+// RequestTimeout is Request with a per-call timeout.
+func (c *Call[Req, Resp]) RequestTimeout(req Req, d time.Duration) (Resp, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), d)
+	defer cancel()
+	return c.Request(ctx, req)
+}
+
+// Requests is the server side of the Call. Receive envelopes and answer
+// each one with Reply, in any order and from any goroutine.
+func (c *Call[Req, Resp]) Requests() <-chan *Envelope[Req, Resp] {
+	return c.requests
+}
+
+// Serve runs handler on n goroutines until ctx is done or the Call is closed.
+// Handler panics are turned into error replies so one bad request can't
+// take a worker down.
+func (c *Call[Req, Resp]) Serve(ctx context.Context, n int, handler func(context.Context, Req) (Resp, error)) {
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case env := <-c.requests:
+					c.handle(env, handler)
+				case <-ctx.Done():
+					return
+				case <-c.closed:
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func (c *Call[Req, Resp]) handle(env *Envelope[Req, Resp], handler func(context.Context, Req) (Resp, error)) {
+	defer func() {
+		if r := recover(); r != nil {
+			var zero Resp
+			env.Reply(zero, fmt.Errorf("request %d: handler: %w", env.ID, panics.Recovered(r)))
+		}
+	}()
+
+	// Skip work for callers that have already timed out
+	if err := env.Ctx.Err(); err != nil {
+		return
+	}
+
+	resp, err := handler(env.Ctx, env.Req)
+	env.Reply(resp, err)
+}
+
+// Close stops Serve and makes pending and future Requests return ErrClosed.
+// The request channel itself stays open, so a racing Request never panics.
+func (c *Call[Req, Resp]) Close() {
+	c.closeOnce.Do(func() { close(c.closed) })
+}
+
+// --- 3. Examples ---
+
+func pingPongWithCall() {
+	fmt.Println("\n=== 1. PING-PONG AS REQUEST/REPLY ===")
+
+	call := NewCall[string, string](0)
+	defer call.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go call.Serve(ctx, 1, func(_ context.Context, msg string) (string, error) {
+		fmt.Printf("Ping received: %s\n", msg)
+		return "pong", nil
+	})
+
+	for i := 1; i <= 3; i++ {
+		resp, err := call.Request(ctx, "ping")
+		if err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
+		fmt.Printf("Pong received: %s\n", resp)
+	}
+}
+
+func perCallTimeout() {
+	fmt.Println("\n=== 2. PER-CALL TIMEOUT ===")
+
+	call := NewCall[time.Duration, string](0)
+	defer call.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go call.Serve(ctx, 2, func(ctx context.Context, work time.Duration) (string, error) {
+		select {
+		case <-time.After(work):
+			return fmt.Sprintf("finished %s of work", work), nil
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	})
+
+	for _, work := range []time.Duration{50 * time.Millisecond, 500 * time.Millisecond} {
+		resp, err := call.RequestTimeout(work, 200*time.Millisecond)
+		if err != nil {
+			fmt.Println("Error:", err)
+			continue
+		}
+		fmt.Println("Reply:", resp)
+	}
+}
+
+func multiplexedOutOfOrder() {
+	fmt.Println("\n=== 3. MANY IN-FLIGHT CALLS, REPLIES OUT OF ORDER ===")
+
+	call := NewCall[string, string](8)
+	defer call.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// An actor that batches three requests and answers them in reverse.
+	// Each envelope knows its own caller, so order doesn't matter.
+	go func() {
+		var batch []*Envelope[string, string]
+		for {
+			var env *Envelope[string, string]
+			select {
+			case env = <-call.Requests():
+			case <-ctx.Done():
+				return
+			}
+
+			batch = append(batch, env)
+			if len(batch) < 3 {
+				continue
+			}
+			for i := len(batch) - 1; i >= 0; i-- {
+				e := batch[i]
+				fmt.Printf("Server replying to request #%d\n", e.ID)
+				e.Reply(strings.ToUpper(e.Req), nil)
+			}
+			batch = batch[:0]
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for _, word := range []string{"alpha", "beta", "gamma"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := call.RequestTimeout(word, time.Second)
+			if err != nil {
+				fmt.Println("Error:", err)
+				return
+			}
+			fmt.Printf("Client %q got %q\n", word, resp)
+		}()
+	}
+	wg.Wait()
+}
+
+func handlerPanic() {
+	fmt.Println("\n=== 4. HANDLER PANIC BECOMES AN ERROR REPLY ===")
+
+	call := NewCall[int, int](0)
+	defer call.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go call.Serve(ctx, 1, func(_ context.Context, n int) (int, error) {
+		return 100 / n, nil // Panics for n == 0
+	})
+
+	for _, n := range []int{4, 0, 5} {
+		resp, err := call.Request(ctx, n)
+		if err != nil {
+			fmt.Println("Error:", err)
+			continue
+		}
+		fmt.Printf("100 / %d = %d\n", n, resp)
+	}
+}
+
+func main() {
+	fmt.Println("📨 GO REQUEST/REPLY OVER CHANNELS - COMPLETE GUIDE")
+	fmt.Println("==================================================")
+
+	pingPongWithCall()
+	time.Sleep(300 * time.Millisecond)
+
+	perCallTimeout()
+	time.Sleep(300 * time.Millisecond)
+
+	multiplexedOutOfOrder()
+	time.Sleep(300 * time.Millisecond)
+
+	handlerPanic()
+
+	fmt.Println("\n✅ All examples completed!")
+}
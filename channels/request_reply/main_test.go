@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"sugar/channels/leakcheck"
+	"sugar/panics"
+)
+
+func TestHandlerPanicBecomesPanicError(t *testing.T) {
+	c := NewCall[int, int](0)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	defer c.Close()
+	go c.Serve(ctx, 1, func(_ context.Context, n int) (int, error) {
+		if n == 0 {
+			panic("no zeros")
+		}
+		return n, nil
+	})
+
+	var pe *panics.PanicError
+	if _, err := c.Request(ctx, 0); !errors.As(err, &pe) || pe.Value != "no zeros" {
+		t.Fatalf("Request(0) error = %v, want a *panics.PanicError", err)
+	}
+	if got, err := c.Request(ctx, 7); err != nil || got != 7 {
+		t.Fatalf("Request(7) = %d, %v after a panic, want 7, nil", got, err)
+	}
+}
+
+func TestRequestTimesOut(t *testing.T) {
+	t.Run("not sent", func(t *testing.T) {
+		c := NewCall[int, int](0) // Nobody receives
+		defer c.Close()
+
+		_, err := c.RequestTimeout(1, 10*time.Millisecond)
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("RequestTimeout() = %v, want context.DeadlineExceeded", err)
+		}
+	})
+
+	t.Run("no reply", func(t *testing.T) {
+		c := NewCall[int, int](1) // Buffered, but nobody answers
+		defer c.Close()
+
+		_, err := c.RequestTimeout(1, 10*time.Millisecond)
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("RequestTimeout() = %v, want context.DeadlineExceeded", err)
+		}
+		env := <-c.Requests()
+		env.Reply(2, nil) // A late reply must not block the server
+	})
+}
+
+func TestOutOfOrderRepliesReachTheirCallers(t *testing.T) {
+	const n = 5
+	c := NewCall[int, string](n)
+	defer c.Close()
+
+	type answer struct {
+		req  int
+		resp string
+		err  error
+	}
+	answers := make(chan answer, n)
+	for i := range n {
+		go func() {
+			resp, err := c.RequestTimeout(i, time.Second)
+			answers <- answer{i, resp, err}
+		}()
+	}
+
+	// Collect every envelope first, then answer them newest first
+	seen := make(map[uint64]bool)
+	batch := make([]*Envelope[int, string], 0, n)
+	for range n {
+		env := <-c.Requests()
+		if seen[env.ID] {
+			t.Fatalf("correlation ID %d used twice", env.ID)
+		}
+		seen[env.ID] = true
+		batch = append(batch, env)
+	}
+	for i := len(batch) - 1; i >= 0; i-- {
+		env := batch[i]
+		env.Reply(fmt.Sprintf("reply to %d", env.Req), nil)
+	}
+
+	for range n {
+		a := <-answers
+		if a.err != nil {
+			t.Errorf("request %d: %v", a.req, a.err)
+		} else if want := fmt.Sprintf("reply to %d", a.req); a.resp != want {
+			t.Errorf("request %d got %q, want %q", a.req, a.resp, want)
+		}
+	}
+}
+
+func TestCloseFailsPendingRequests(t *testing.T) {
+	c := NewCall[int, int](0)
+
+	errs := make(chan error, 2)
+	go func() { // Received by the server but never answered
+		_, err := c.Request(context.Background(), 1)
+		errs <- err
+	}()
+	<-c.Requests()
+	go func() { // Still waiting to be received
+		_, err := c.Request(context.Background(), 2)
+		errs <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	c.Close()
+	c.Close() // Safe to call twice
+
+	for range 2 {
+		if err := <-errs; !errors.Is(err, ErrClosed) {
+			t.Errorf("pending Request() = %v, want ErrClosed", err)
+		}
+	}
+	if _, err := c.Request(context.Background(), 3); !errors.Is(err, ErrClosed) {
+		t.Errorf("Request after Close = %v, want ErrClosed", err)
+	}
+}
+
+// Examples run one at a time: leakcheck compares goroutine snapshots, so a
+// parallel test would look like a leak.
+func TestExamplesDoNotLeakOrDeadlock(t *testing.T) {
+	leakcheck.Check(t,
+		leakcheck.Example{Name: "pingPongWithCall", Fn: pingPongWithCall},
+		leakcheck.Example{Name: "perCallTimeout", Fn: perCallTimeout},
+		leakcheck.Example{Name: "multiplexedOutOfOrder", Fn: multiplexedOutOfOrder},
+		leakcheck.Example{Name: "handlerPanic", Fn: handlerPanic},
+	)
+}
@@ -0,0 +1,285 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// --- 1. Signal: Close-Once Broadcast ---
+
+// Signal is a one-shot event that any number of goroutines can wait for.
+// It is the "close a chan struct{} to broadcast" idiom without the
+// "close of closed channel" panic. The zero value is ready to use.
+type Signal struct {
+	mu    sync.Mutex
+	ch    chan struct{}
+	fired bool
+}
+
+// NewSignal creates a Signal. Using a zero Signal works just as well.
+func NewSignal() *Signal {
+	return &Signal{}
+}
+
+// channel lazily creates the underlying channel. Must be called with s.mu held.
+func (s *Signal) channel() chan struct{} {
+	if s.ch == nil {
+		s.ch = make(chan struct{})
+	}
+	return s.ch
+}
+
+// Done returns a channel that is closed once Fire has been called.
+func (s *Signal) Done() <-chan struct{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.channel()
+}
+
+// Fire releases every waiter. Only the first call has an effect;
+// it reports whether this call was the one that fired.
+func (s *Signal) Fire() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.fired {
+		return false
+	}
+	s.fired = true
+	close(s.channel())
+	return true
+}
+
+// Fired reports whether Fire has been called.
+func (s *Signal) Fired() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.fired
+}
+
+// --- 2. SafeChan: A Channel With Multiple Owners ---
+
+var (
+	// ErrClosed is returned when sending on a closed SafeChan.
+	ErrClosed = errors.New("safechan: send on closed channel")
+	// ErrFull is returned by TrySend when the buffer has no room.
+	ErrFull = errors.New("safechan: channel full")
+)
+
+// SafeChan wraps a channel so that closing twice and sending after close
+// return errors instead of panicking. Any number of owners may call Close.
+type SafeChan[T any] struct {
+	ch chan T
+
+	// Senders hold mu for reading while they send; Close takes it for
+	// writing, so close(ch) can never race with a send.
+	mu     sync.RWMutex
+	closed bool
+
+	// closing fires before Close waits for mu, waking up blocked senders
+	closing Signal
+}
+
+// NewSafeChan creates a SafeChan with the given buffer size.
+func NewSafeChan[T any](size int) *SafeChan[T] {
+	return &SafeChan[T]{ch: make(chan T, size)}
+}
+
+// C returns the receive side. Use `val, ok := <-c.C()` or range as usual.
+func (c *SafeChan[T]) C() <-chan T {
+	return c.ch
+}
+
+// TrySend sends v without blocking. It returns ErrClosed after Close
+// and ErrFull when no receiver or buffer slot is ready.
+func (c *SafeChan[T]) TrySend(v T) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return ErrClosed
+	}
+	select {
+	case c.ch <- v:
+		return nil
+	default:
+		return ErrFull
+	}
+}
+
+// Send blocks until v is sent, ctx is done, or the channel is closed.
+func (c *SafeChan[T]) Send(ctx context.Context, v T) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return ErrClosed
+	}
+	select {
+	case c.ch <- v:
+		return nil
+	case <-c.closing.Done():
+		return ErrClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close closes the channel. It is safe to call from several goroutines and
+// more than once; it reports whether this call did the closing.
+func (c *SafeChan[T]) Close() bool {
+	if !c.closing.Fire() {
+		return false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.closed = true
+	close(c.ch)
+	return true
+}
+
+// IsClosed reports whether Close has been called. Treat the answer as a
+// hint: another owner may close the channel right after it returns.
+func (c *SafeChan[T]) IsClosed() bool {
+	return c.closing.Fired()
+}
+
+// --- 3. Examples ---
+
+func doubleClose() {
+	fmt.Println("\n=== 1. CLOSING TWICE ===")
+
+	// Plain channel: the second close panics
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				fmt.Println("Plain channel:", r)
+			}
+		}()
+		ch := make(chan int)
+		close(ch)
+		//sugarlint:ignore closedsend shows the double-close panic SafeChan prevents
+		close(ch)
+	}()
+
+	// SafeChan: the second close is a no-op
+	sc := NewSafeChan[int](0)
+	fmt.Println("SafeChan first Close closed it:", sc.Close())
+	fmt.Println("SafeChan second Close closed it:", sc.Close())
+}
+
+func sendAfterClose() {
+	fmt.Println("\n=== 2. SENDING ON A CLOSED CHANNEL ===")
+
+	// Plain channel: send on closed channel panics
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				fmt.Println("Plain channel:", r)
+			}
+		}()
+		ch := make(chan int, 1)
+		close(ch)
+		//sugarlint:ignore closedsend shows the send-on-closed panic TrySend prevents
+		ch <- 1
+	}()
+
+	sc := NewSafeChan[int](1)
+	fmt.Println("TrySend before close:", sc.TrySend(1))
+	fmt.Println("TrySend on full buffer:", sc.TrySend(2))
+	sc.Close()
+	fmt.Println("TrySend after close:", sc.TrySend(3))
+	fmt.Println("IsClosed:", sc.IsClosed())
+
+	// Buffered values can still be read, then ok=false - same as closingChannels
+	for {
+		val, ok := <-sc.C()
+		if !ok {
+			fmt.Println("Channel is closed (ok=false)")
+			break
+		}
+		fmt.Println("Value:", val)
+	}
+}
+
+func multipleOwners() {
+	fmt.Println("\n=== 3. MULTIPLE OWNERS CLOSING THE SAME CHANNEL ===")
+
+	sc := NewSafeChan[string](0)
+
+	// A consumer that stops after a few messages
+	go func() {
+		for i := 0; i < 3; i++ {
+			fmt.Println("Consumer got:", <-sc.C())
+		}
+	}()
+
+	// Three producers; each closes the channel when it hits an error.
+	// With a plain channel, the second close (or any later send) would panic.
+	var wg sync.WaitGroup
+	for p := 1; p <= 3; p++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 1; ; i++ {
+				ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+				err := sc.Send(ctx, fmt.Sprintf("producer %d, message %d", p, i))
+				cancel()
+
+				if err != nil {
+					if sc.Close() {
+						fmt.Printf("Producer %d closed the channel (%v)\n", p, err)
+					}
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	fmt.Println("No panic, IsClosed:", sc.IsClosed())
+}
+
+func signalBroadcast() {
+	fmt.Println("\n=== 4. SIGNAL: CLOSE-ONCE BROADCAST ===")
+
+	var shutdown Signal // Zero value is ready to use
+
+	var wg sync.WaitGroup
+	for w := 1; w <= 3; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-shutdown.Done()
+			fmt.Printf("Worker %d: shutting down\n", w)
+		}()
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	fmt.Println("First Fire:", shutdown.Fire())
+	fmt.Println("Second Fire:", shutdown.Fire()) // No panic, just false
+	wg.Wait()
+}
+
+func main() {
+	fmt.Println("🔒 GO SAFE CHANNEL CLOSING - COMPLETE GUIDE")
+	fmt.Println("===========================================")
+
+	doubleClose()
+	time.Sleep(300 * time.Millisecond)
+
+	sendAfterClose()
+	time.Sleep(300 * time.Millisecond)
+
+	multipleOwners()
+	time.Sleep(300 * time.Millisecond)
+
+	signalBroadcast()
+
+	fmt.Println("\n✅ All examples completed!")
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"sugar/channels/leakcheck"
+)
+
+// Run with -race: senders and closers hit the same SafeChan at once, and a
+// send that slipped past Close would panic with "send on closed channel".
+func TestConcurrentCloseAndSend(t *testing.T) {
+	for range 50 {
+		c := NewSafeChan[int](4)
+
+		var received atomic.Int64
+		drained := make(chan struct{})
+		go func() {
+			defer close(drained)
+			for range c.C() {
+				received.Add(1)
+			}
+		}()
+
+		var sent, closers atomic.Int64
+		var wg sync.WaitGroup
+		for i := range 8 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := range 20 {
+					var err error
+					if j%2 == 0 {
+						err = c.TrySend(i)
+					} else {
+						err = c.Send(context.Background(), i)
+					}
+					switch {
+					case err == nil:
+						sent.Add(1)
+					case !errors.Is(err, ErrClosed) && !errors.Is(err, ErrFull):
+						t.Errorf("send: unexpected error %v", err)
+					}
+				}
+			}()
+		}
+		for range 4 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if c.Close() {
+					closers.Add(1)
+				}
+			}()
+		}
+		wg.Wait()
+		<-drained
+
+		if n := closers.Load(); n != 1 {
+			t.Fatalf("%d Close calls reported closing, want exactly 1", n)
+		}
+		if s, r := sent.Load(), received.Load(); s != r {
+			t.Fatalf("%d sends succeeded but %d values were received", s, r)
+		}
+		if err := c.TrySend(0); !errors.Is(err, ErrClosed) {
+			t.Fatalf("TrySend after Close = %v, want ErrClosed", err)
+		}
+	}
+}
+
+func TestConcurrentSignalFire(t *testing.T) {
+	var s Signal // The zero value must work
+	var fired atomic.Int64
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-s.Done()
+		}()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if s.Fire() {
+				fired.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := fired.Load(); n != 1 {
+		t.Fatalf("%d Fire calls reported firing, want exactly 1", n)
+	}
+	if !s.Fired() {
+		t.Fatal("Fired() = false after Fire")
+	}
+}
+
+// Examples run one at a time: leakcheck compares goroutine snapshots, so a
+// parallel test would look like a leak.
+func TestExamplesDoNotLeakOrDeadlock(t *testing.T) {
+	leakcheck.Check(t,
+		leakcheck.Example{Name: "doubleClose", Fn: doubleClose},
+		leakcheck.Example{Name: "sendAfterClose", Fn: sendAfterClose},
+		leakcheck.Example{Name: "multipleOwners", Fn: multipleOwners},
+		leakcheck.Example{Name: "signalBroadcast", Fn: signalBroadcast},
+	)
+}
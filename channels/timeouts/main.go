@@ -0,0 +1,224 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"sugar/clock"
+)
+
+// --- 1. Pooled Timers ---
+
+// The helpers take a clock.Clock: production code passes clock.Real, tests
+// a clock.FakeClock that they move forward by hand instead of sleeping.
+//
+// Timers of the real clock are pooled, so a receive loop does not allocate
+// a new timer on every iteration the way `case <-time.After(d)` does.
+var timerPool sync.Pool
+
+// newTimer returns a timer on clk, reusing a pooled one for clock.Real.
+func newTimer(clk clock.Clock, d time.Duration) clock.Timer {
+	if clk == clock.Real {
+		if t, ok := timerPool.Get().(clock.Timer); ok {
+			// Since Go 1.23 Reset also discards a value left in the channel,
+			// so a pooled timer never fires early with a stale tick.
+			t.Reset(d)
+			return t
+		}
+	}
+	return clk.NewTimer(d)
+}
+
+// releaseTimer stops t and, for the real clock, returns it to the pool.
+// t must not be used afterwards.
+func releaseTimer(clk clock.Clock, t clock.Timer) {
+	t.Stop()
+	if clk == clock.Real {
+		timerPool.Put(t)
+	}
+}
+
+// --- 2. Timeout Helpers ---
+
+// ErrTimeout is returned when a channel operation does not complete in time.
+var ErrTimeout = errors.New("channel operation timed out")
+
+// RecvTimeout receives from ch, waiting at most d.
+// ok is false if the channel was closed, like `v, ok := <-ch`.
+func RecvTimeout[T any](clk clock.Clock, ch <-chan T, d time.Duration) (v T, ok bool, err error) {
+	t := newTimer(clk, d)
+	defer releaseTimer(clk, t)
+
+	select {
+	case v, ok = <-ch:
+		return v, ok, nil
+	case <-t.C():
+		return v, false, ErrTimeout
+	}
+}
+
+// SendTimeout sends v on ch, waiting at most d.
+func SendTimeout[T any](clk clock.Clock, ch chan<- T, v T, d time.Duration) error {
+	t := newTimer(clk, d)
+	defer releaseTimer(clk, t)
+
+	select {
+	case ch <- v:
+		return nil
+	case <-t.C():
+		return ErrTimeout
+	}
+}
+
+// RecvContext receives from ch until ctx is done. No timer is needed:
+// the deadline, if any, lives in ctx.
+func RecvContext[T any](ctx context.Context, ch <-chan T) (v T, ok bool, err error) {
+	select {
+	case v, ok = <-ch:
+		return v, ok, nil
+	case <-ctx.Done():
+		return v, false, ctx.Err()
+	}
+}
+
+// SendContext sends v on ch until ctx is done.
+func SendContext[T any](ctx context.Context, ch chan<- T, v T) error {
+	select {
+	case ch <- v:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// --- 3. Idle Watchdog ---
+
+// Watchdog fires when it has not been kicked for a whole period. It uses
+// one timer for its entire life, reset on every Kick.
+type Watchdog struct {
+	clk    clock.Clock
+	timer  clock.Timer
+	period time.Duration
+}
+
+// NewWatchdog starts a watchdog that fires after period of inactivity.
+func NewWatchdog(clk clock.Clock, period time.Duration) *Watchdog {
+	return &Watchdog{clk: clk, timer: newTimer(clk, period), period: period}
+}
+
+// C receives a value when the watchdog fires. Use it as a select case.
+func (w *Watchdog) C() <-chan time.Time { return w.timer.C() }
+
+// Kick restarts the idle period. Call it whenever a message arrives.
+func (w *Watchdog) Kick() { w.timer.Reset(w.period) }
+
+// Stop releases the watchdog's timer.
+func (w *Watchdog) Stop() { releaseTimer(w.clk, w.timer) }
+
+// --- 4. Examples ---
+
+func recvAndSendTimeout() {
+	fmt.Println("\n=== 1. RECEIVE / SEND WITH TIMEOUT ===")
+
+	ch := make(chan string)
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		ch <- "Data arrived"
+	}()
+
+	v, _, err := RecvTimeout(clock.Real, ch, 200*time.Millisecond)
+	fmt.Printf("RecvTimeout: %q, err=%v\n", v, err)
+
+	_, _, err = RecvTimeout(clock.Real, ch, 50*time.Millisecond)
+	fmt.Println("RecvTimeout with no sender:", err)
+
+	err = SendTimeout(clock.Real, ch, "nobody listens", 50*time.Millisecond)
+	fmt.Println("SendTimeout with no receiver:", err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, _, err = RecvContext(ctx, ch)
+	fmt.Println("RecvContext with deadline:", err)
+}
+
+func selectInLoopWithWatchdog() {
+	fmt.Println("\n=== 2. SELECT IN LOOP WITH ONE REUSED TIMER ===")
+
+	ch := make(chan int, 5)
+
+	go func() {
+		for i := 1; i <= 5; i++ {
+			ch <- i
+			time.Sleep(30 * time.Millisecond)
+		}
+		// No close: the watchdog has to end the loop
+	}()
+
+	// Same loop as selectInLoop, but without time.After in every iteration
+	wd := NewWatchdog(clock.Real, 100*time.Millisecond)
+	defer wd.Stop()
+
+	for {
+		select {
+		case val := <-ch:
+			wd.Kick()
+			fmt.Printf("Received: %d\n", val)
+		case <-wd.C():
+			fmt.Println("Idle for 100ms, exiting loop")
+			return
+		}
+	}
+}
+
+func fakeClockWatchdog() {
+	fmt.Println("\n=== 3. FAKE CLOCK: AN HOUR OF IDLENESS IN NO TIME ===")
+
+	clk := clock.NewFakeClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	wd := NewWatchdog(clk, time.Hour)
+	defer wd.Stop()
+
+	start := time.Now()
+
+	clk.Advance(59 * time.Minute)
+	wd.Kick() // A message arrived just in time
+	clk.Advance(59 * time.Minute)
+
+	select {
+	case <-wd.C():
+		fmt.Println("Fired too early!")
+	default:
+		fmt.Println("After 2x59min with a kick in between: not fired")
+	}
+
+	clk.Advance(time.Minute)
+	select {
+	case at := <-wd.C():
+		fmt.Printf("Fired at %s\n", at.Format("15:04"))
+	default:
+		fmt.Println("Should have fired!")
+	}
+
+	fmt.Printf("Real time spent: %s\n", time.Since(start).Round(time.Millisecond))
+}
+
+func main() {
+	fmt.Println("⏰ GO CHANNEL TIMEOUTS - COMPLETE GUIDE")
+	fmt.Println("=======================================")
+
+	recvAndSendTimeout()
+	time.Sleep(300 * time.Millisecond)
+
+	selectInLoopWithWatchdog()
+	time.Sleep(300 * time.Millisecond)
+
+	fakeClockWatchdog()
+
+	// The allocation comparison with time.After lives in main_test.go:
+	//   go test -bench . -benchmem ./channels/timeouts
+
+	fmt.Println("\n✅ All examples completed!")
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"sugar/clock"
+
+	"sugar/channels/leakcheck"
+)
+
+func TestRecvTimeoutOnFakeClock(t *testing.T) {
+	clk := clock.NewFakeClock(time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC))
+	ch := make(chan int)
+
+	errs := make(chan error, 1)
+	go func() {
+		_, _, err := RecvTimeout(clk, ch, time.Hour)
+		errs <- err
+	}()
+
+	clk.BlockUntil(1)
+	clk.Advance(time.Hour)
+	if err := <-errs; !errors.Is(err, ErrTimeout) {
+		t.Fatalf("RecvTimeout() = %v, want ErrTimeout", err)
+	}
+}
+
+func TestWatchdogKickPostponesFiring(t *testing.T) {
+	clk := clock.NewFakeClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	wd := NewWatchdog(clk, time.Hour)
+	defer wd.Stop()
+
+	clk.Advance(59 * time.Minute)
+	wd.Kick()
+	clk.Advance(59 * time.Minute)
+	select {
+	case <-wd.C():
+		t.Fatal("watchdog fired although it was kicked")
+	default:
+	}
+
+	clk.Advance(time.Minute)
+	select {
+	case <-wd.C():
+	default:
+		t.Fatal("watchdog did not fire after a full idle period")
+	}
+}
+
+// --- Allocations: time.After vs pooled timer ---
+
+func BenchmarkTimeAfter(b *testing.B) {
+	b.ReportAllocs()
+	ch := make(chan int, 1)
+	for i := 0; b.Loop(); i++ {
+		ch <- i
+		select {
+		case <-ch:
+		//sugarlint:ignore timeafter the allocation baseline this benchmark measures
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+func BenchmarkRecvTimeout(b *testing.B) {
+	b.ReportAllocs()
+	ch := make(chan int, 1)
+	for i := 0; b.Loop(); i++ {
+		ch <- i
+		RecvTimeout(clock.Real, ch, time.Second)
+	}
+}
+
+// Examples run one at a time: leakcheck compares goroutine snapshots, so a
+// parallel test would look like a leak.
+func TestExamplesDoNotLeakOrDeadlock(t *testing.T) {
+	leakcheck.Check(t,
+		leakcheck.Example{Name: "recvAndSendTimeout", Fn: recvAndSendTimeout},
+		leakcheck.Example{Name: "selectInLoopWithWatchdog", Fn: selectInLoopWithWatchdog},
+		leakcheck.Example{Name: "fakeClockWatchdog", Fn: fakeClockWatchdog},
+	)
+}
@@ -0,0 +1,276 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// --- 1. The Ring Buffer ---
+
+// ring is a FIFO queue over a circular slice. It doubles when full and
+// halves when three quarters empty, so a burst doesn't pin memory forever.
+type ring[T any] struct {
+	buf        []T
+	head, size int
+	minCap     int
+}
+
+func newRing[T any](minCap int) *ring[T] {
+	return &ring[T]{buf: make([]T, minCap), minCap: minCap}
+}
+
+func (r *ring[T]) len() int { return r.size }
+
+func (r *ring[T]) push(v T) {
+	if r.size == len(r.buf) {
+		r.resize(2 * len(r.buf))
+	}
+	r.buf[(r.head+r.size)%len(r.buf)] = v
+	r.size++
+}
+
+func (r *ring[T]) peek() T {
+	return r.buf[r.head]
+}
+
+func (r *ring[T]) pop() T {
+	var zero T
+	v := r.buf[r.head]
+	r.buf[r.head] = zero // Let the GC collect the value
+	r.head = (r.head + 1) % len(r.buf)
+	r.size--
+
+	if len(r.buf) > r.minCap && r.size <= len(r.buf)/4 {
+		r.resize(len(r.buf) / 2)
+	}
+	return v
+}
+
+// resize copies the elements, in order, to the front of a new slice.
+func (r *ring[T]) resize(n int) {
+	buf := make([]T, n)
+	for i := 0; i < r.size; i++ {
+		buf[i] = r.buf[(r.head+i)%len(r.buf)]
+	}
+	r.buf = buf
+	r.head = 0
+}
+
+// --- 2. The Unbounded Channel ---
+
+// OverflowError is returned by Send when the buffer is at its hard limit.
+type OverflowError struct {
+	Buffered int
+	Limit    int
+}
+
+func (e *OverflowError) Error() string {
+	return fmt.Sprintf("unbounded: buffer overflow: %d items buffered, hard limit is %d", e.Buffered, e.Limit)
+}
+
+// ErrClosed is returned by Send after Close.
+var ErrClosed = errors.New("unbounded: send after close")
+
+// Options configures an Unbounded channel. The zero value means no limits.
+type Options struct {
+	// SoftLimit triggers OnSoftLimit each time the buffer grows past it.
+	SoftLimit   int
+	OnSoftLimit func(buffered int)
+
+	// HardLimit caps the buffer. At the limit the pump stops reading In(),
+	// so plain channel sends block and Send returns an *OverflowError.
+	HardLimit int
+}
+
+// Unbounded is a channel pair with a growable buffer between them.
+// Producers never block on a slow consumer (up to HardLimit).
+type Unbounded[T any] struct {
+	in    chan T
+	out   chan T
+	sends chan sendReq[T] // Send's requests, answered by the pump
+	done  chan struct{}   // Closed when the pump exits
+	opts  Options
+
+	buffered atomic.Int64 // Items in the ring, readable without the pump
+
+	closed    atomic.Bool
+	closeOnce sync.Once
+}
+
+// sendReq asks the pump to buffer v. The pump alone knows how full the
+// buffer is, so it makes the accept/reject decision and replies on ack.
+type sendReq[T any] struct {
+	v   T
+	ack chan error
+}
+
+// NewUnbounded starts the pump goroutine that moves values from In to Out.
+func NewUnbounded[T any](opts Options) *Unbounded[T] {
+	u := &Unbounded[T]{
+		in:    make(chan T),
+		out:   make(chan T),
+		sends: make(chan sendReq[T]),
+		done:  make(chan struct{}),
+		opts:  opts,
+	}
+	go u.pump()
+	return u
+}
+
+// In is the send side. Close it (or call Close) when done sending.
+func (u *Unbounded[T]) In() chan<- T { return u.in }
+
+// Out is the receive side. It is closed after In is closed and every
+// buffered value has been received.
+func (u *Unbounded[T]) Out() <-chan T { return u.out }
+
+// Len returns the number of values waiting in the buffer.
+func (u *Unbounded[T]) Len() int { return int(u.buffered.Load()) }
+
+// Send is like `u.In() <- v`, but returns an *OverflowError instead of
+// blocking when the buffer is at its hard limit, and ErrClosed instead of
+// panicking after Close.
+func (u *Unbounded[T]) Send(v T) error {
+	if u.closed.Load() {
+		return ErrClosed
+	}
+
+	req := sendReq[T]{v: v, ack: make(chan error, 1)}
+	select {
+	case u.sends <- req:
+		return <-req.ack
+	case <-u.done:
+		return ErrClosed
+	}
+}
+
+// Close closes In. Calling it more than once is safe.
+func (u *Unbounded[T]) Close() {
+	u.closeOnce.Do(func() {
+		u.closed.Store(true)
+		close(u.in)
+	})
+}
+
+func (u *Unbounded[T]) pump() {
+	defer close(u.done)
+	defer close(u.out)
+
+	buf := newRing[T](16)
+	in := u.in
+	overSoft := false
+
+	for in != nil || buf.len() > 0 {
+		// The nil-channel trick from nilChannelBehavior: a nil channel
+		// disables its select case.
+		var out chan T
+		var next T
+		if buf.len() > 0 {
+			out = u.out
+			next = buf.peek()
+		}
+
+		recv := in
+		if u.opts.HardLimit > 0 && buf.len() >= u.opts.HardLimit {
+			recv = nil // Full: apply back-pressure to In()
+		}
+
+		select {
+		case v, ok := <-recv:
+			if !ok {
+				in = nil // Closed: drain what's left, then stop
+				continue
+			}
+			buf.push(v)
+
+		case req := <-u.sends:
+			// Checked here rather than against Len in Send: a check there
+			// would be stale by the time the value reached the pump
+			switch limit := u.opts.HardLimit; {
+			case in == nil:
+				req.ack <- ErrClosed
+			case limit > 0 && buf.len() >= limit:
+				req.ack <- &OverflowError{Buffered: buf.len(), Limit: limit}
+			default:
+				buf.push(req.v)
+				req.ack <- nil
+			}
+
+		case out <- next:
+			buf.pop()
+		}
+		u.buffered.Store(int64(buf.len()))
+
+		if limit := u.opts.SoftLimit; limit > 0 {
+			if !overSoft && buf.len() > limit && u.opts.OnSoftLimit != nil {
+				u.opts.OnSoftLimit(buf.len())
+			}
+			overSoft = buf.len() > limit
+		}
+	}
+}
+
+// --- 3. Examples ---
+
+func burstyProducer() {
+	fmt.Println("\n=== 1. BURSTY PRODUCER, SLOW CONSUMER ===")
+
+	u := NewUnbounded[int](Options{
+		SoftLimit:   500,
+		OnSoftLimit: func(n int) { fmt.Printf("Warning: %d events buffered\n", n) },
+	})
+
+	// Unlike make(chan int, 3), the producer never blocks on the reader
+	start := time.Now()
+	for i := 1; i <= 1000; i++ {
+		u.In() <- i
+	}
+	u.Close()
+	fmt.Printf("Produced 1000 events in %s without a reader\n", time.Since(start).Round(time.Millisecond))
+
+	sum := 0
+	for v := range u.Out() {
+		sum += v
+	}
+	fmt.Printf("Consumer drained everything after close, sum=%d\n", sum)
+}
+
+func hardLimit() {
+	fmt.Println("\n=== 2. HARD LIMIT WITH TYPED OVERFLOW ERROR ===")
+
+	u := NewUnbounded[string](Options{HardLimit: 3})
+
+	for i := 1; i <= 5; i++ {
+		err := u.Send(fmt.Sprintf("event-%d", i))
+
+		var ofe *OverflowError
+		if errors.As(err, &ofe) {
+			fmt.Printf("Send %d rejected: %d/%d buffered\n", i, ofe.Buffered, ofe.Limit)
+			continue
+		}
+		fmt.Printf("Send %d accepted\n", i)
+	}
+
+	u.Close()
+	for v := range u.Out() {
+		fmt.Println("Received:", v)
+	}
+}
+
+func main() {
+	fmt.Println("🪣 GO UNBOUNDED CHANNEL - COMPLETE GUIDE")
+	fmt.Println("========================================")
+
+	burstyProducer()
+	time.Sleep(300 * time.Millisecond)
+
+	hardLimit()
+
+	// The comparison against fixed buffers lives in main_test.go:
+	//   go test -bench . ./channels/unbounded
+
+	fmt.Println("\n✅ All examples completed!")
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"sugar/channels/leakcheck"
+)
+
+func TestSendAtHardLimitNeverBlocks(t *testing.T) {
+	const limit, senders = 3, 50
+
+	u := NewUnbounded[int](Options{HardLimit: limit})
+
+	var wg sync.WaitGroup
+	errs := make(chan error, senders)
+	for i := range senders {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- u.Send(i)
+		}()
+	}
+	wg.Wait() // Hangs if a sender blocks at the limit
+	close(errs)
+
+	accepted := 0
+	for err := range errs {
+		var ofe *OverflowError
+		switch {
+		case err == nil:
+			accepted++
+		case !errors.As(err, &ofe):
+			t.Fatalf("Send() = %v, want nil or *OverflowError", err)
+		}
+	}
+	if accepted != limit {
+		t.Fatalf("%d sends accepted, want exactly %d", accepted, limit)
+	}
+	if n := u.Len(); n != limit {
+		t.Fatalf("Len() = %d, want %d", n, limit)
+	}
+}
+
+func TestSendAfterClose(t *testing.T) {
+	u := NewUnbounded[int](Options{})
+	if err := u.Send(1); err != nil {
+		t.Fatalf("Send() = %v", err)
+	}
+	u.Close()
+
+	if err := u.Send(2); !errors.Is(err, ErrClosed) {
+		t.Fatalf("Send after Close = %v, want ErrClosed", err)
+	}
+	if v := <-u.Out(); v != 1 {
+		t.Fatalf("received %d, want 1", v)
+	}
+	if err := u.Send(3); !errors.Is(err, ErrClosed) {
+		t.Fatalf("Send after drain = %v, want ErrClosed", err)
+	}
+}
+
+// --- Benchmarks against fixed buffers ---
+
+const burst = 1000
+
+func BenchmarkChanBuffer1(b *testing.B) {
+	for b.Loop() {
+		ch := make(chan int, 1)
+		go func() {
+			for j := 0; j < burst; j++ {
+				ch <- j
+			}
+			close(ch)
+		}()
+		for range ch {
+		}
+	}
+}
+
+func BenchmarkChanBuffer1000(b *testing.B) {
+	for b.Loop() {
+		ch := make(chan int, burst)
+		for j := 0; j < burst; j++ {
+			ch <- j
+		}
+		close(ch)
+		for range ch {
+		}
+	}
+}
+
+func BenchmarkUnbounded(b *testing.B) {
+	for b.Loop() {
+		u := NewUnbounded[int](Options{})
+		for j := 0; j < burst; j++ {
+			u.In() <- j
+		}
+		u.Close()
+		for range u.Out() {
+		}
+	}
+}
+
+// Examples run one at a time: leakcheck compares goroutine snapshots, so a
+// parallel test would look like a leak.
+func TestExamplesDoNotLeakOrDeadlock(t *testing.T) {
+	leakcheck.Check(t,
+		leakcheck.Example{Name: "burstyProducer", Fn: burstyProducer},
+		leakcheck.Example{Name: "hardLimit", Fn: hardLimit},
+	)
+}
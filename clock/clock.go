@@ -0,0 +1,254 @@
+// Package clock is the time package behind an interface, so timing code
+// can be tested on a FakeClock that only moves when the test says so:
+//
+//	func retry(clk clock.Clock, backoff time.Duration, op func() error) error {
+//		...
+//		clk.Sleep(backoff)
+//	}
+//
+//	clk := clock.NewFakeClock(start)
+//	go retry(clk, time.Second, op)
+//	clk.BlockUntil(1) // retry is sleeping
+//	clk.Advance(time.Second)
+//
+// Production code passes clock.Real.
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// --- 1. The Clock Interface ---
+
+// Clock is everything timing code needs from the time package.
+// Code that takes a Clock instead of calling time.Sleep directly can be
+// tested with a FakeClock in microseconds instead of seconds.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+	NewTimer(d time.Duration) Timer
+	NewTicker(d time.Duration) Ticker
+	Sleep(d time.Duration)
+}
+
+// Timer mirrors *time.Timer.
+type Timer interface {
+	C() <-chan time.Time
+	Stop() bool
+	Reset(d time.Duration) bool
+}
+
+// Ticker mirrors *time.Ticker.
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+	Reset(d time.Duration)
+}
+
+// --- 2. The Real Clock ---
+
+// Real is the Clock backed by the time package.
+var Real Clock = realClock{}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+func (realClock) Sleep(d time.Duration)                  { time.Sleep(d) }
+func (realClock) NewTimer(d time.Duration) Timer         { return realTimer{time.NewTimer(d)} }
+func (realClock) NewTicker(d time.Duration) Ticker       { return realTicker{time.NewTicker(d)} }
+
+type realTimer struct{ *time.Timer }
+
+func (t realTimer) C() <-chan time.Time { return t.Timer.C }
+
+type realTicker struct{ *time.Ticker }
+
+func (t realTicker) C() <-chan time.Time { return t.Ticker.C }
+
+// --- 3. The Fake Clock ---
+
+// FakeClock stands still until Advance is called. Timers, tickers and
+// sleepers registered on it fire in deadline order as time moves.
+type FakeClock struct {
+	mu      sync.Mutex
+	changed *sync.Cond // Broadcast whenever the set of waiters changes
+	now     time.Time
+	waiters []*fakeWaiter
+}
+
+// fakeWaiter backs timers, tickers, After and Sleep.
+type fakeWaiter struct {
+	clock  *FakeClock
+	when   time.Time
+	period time.Duration // Non-zero for tickers
+	ch     chan time.Time
+}
+
+// NewFakeClock creates a fake clock set to start.
+func NewFakeClock(start time.Time) *FakeClock {
+	c := &FakeClock{now: start}
+	c.changed = sync.NewCond(&c.mu)
+	return c
+}
+
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// NewTimer creates a timer that fires once the clock has moved d forward.
+// Like time.NewTimer, a timer with d <= 0 fires immediately.
+func (c *FakeClock) NewTimer(d time.Duration) Timer {
+	return c.add(d, 0)
+}
+
+func (c *FakeClock) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("clock: non-positive interval for NewTicker")
+	}
+	return fakeTicker{c.add(d, d)}
+}
+
+func (c *FakeClock) After(d time.Duration) <-chan time.Time {
+	return c.NewTimer(d).C()
+}
+
+// Sleep blocks until another goroutine advances the clock by d.
+// Like time.Sleep, it returns at once if d <= 0.
+func (c *FakeClock) Sleep(d time.Duration) {
+	<-c.After(d)
+}
+
+func (c *FakeClock) add(d, period time.Duration) *fakeWaiter {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	w := &fakeWaiter{clock: c, period: period, ch: make(chan time.Time, 1)}
+	c.schedule(w, d)
+	return w
+}
+
+// schedule arms w to fire d from now. A one-shot waiter that is already due
+// fires right away instead of waiting for an Advance that may never come.
+// Must be called with c.mu held.
+func (c *FakeClock) schedule(w *fakeWaiter, d time.Duration) {
+	w.when = c.now.Add(d)
+	if d <= 0 && w.period == 0 {
+		w.ch <- c.now // Empty: new, or drained by the caller
+		return
+	}
+	c.waiters = append(c.waiters, w)
+	c.changed.Broadcast()
+}
+
+// remove must be called with c.mu held. It reports whether w was pending.
+func (c *FakeClock) remove(w *fakeWaiter) bool {
+	for i, other := range c.waiters {
+		if other == w {
+			c.waiters = append(c.waiters[:i], c.waiters[i+1:]...)
+			c.changed.Broadcast()
+			return true
+		}
+	}
+	return false
+}
+
+// Advance moves time forward by d, firing everything that comes due on the
+// way. A ticker crossed several times fires once per period, but like a
+// real ticker it drops ticks its reader hasn't picked up.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	target := c.now.Add(d)
+	for {
+		next := c.earliest()
+		if next == nil || next.when.After(target) {
+			break
+		}
+
+		c.now = next.when
+		select {
+		case next.ch <- c.now:
+		default:
+		}
+
+		if next.period > 0 {
+			next.when = next.when.Add(next.period)
+		} else {
+			c.remove(next)
+		}
+	}
+	c.now = target
+}
+
+func (c *FakeClock) earliest() *fakeWaiter {
+	var first *fakeWaiter
+	for _, w := range c.waiters {
+		if first == nil || w.when.Before(first.when) {
+			first = w
+		}
+	}
+	return first
+}
+
+// BlockUntil waits until at least n timers, tickers or sleepers are
+// pending. Tests call it before Advance so they don't move time before the
+// code under test has started waiting.
+func (c *FakeClock) BlockUntil(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for len(c.waiters) < n {
+		c.changed.Wait()
+	}
+}
+
+func (w *fakeWaiter) C() <-chan time.Time { return w.ch }
+
+// Stop also drops a tick that fired but was never received, as Go 1.23+
+// timers do, so a stopped timer's channel never delivers a stale value.
+func (w *fakeWaiter) Stop() bool {
+	w.clock.mu.Lock()
+	defer w.clock.mu.Unlock()
+
+	pending := w.clock.remove(w)
+	select {
+	case <-w.ch:
+	default:
+	}
+	return pending
+}
+
+func (w *fakeWaiter) Reset(d time.Duration) bool {
+	c := w.clock
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	pending := c.remove(w)
+	select {
+	case <-w.ch: // Drop a stale tick, as Go 1.23+ timers do
+	default:
+	}
+
+	if w.period > 0 {
+		w.period = d
+	}
+	c.schedule(w, d)
+	return pending
+}
+
+// fakeTicker adapts fakeWaiter to the Ticker method set.
+type fakeTicker struct{ w *fakeWaiter }
+
+func (t fakeTicker) C() <-chan time.Time { return t.w.C() }
+func (t fakeTicker) Stop()               { t.w.Stop() }
+func (t fakeTicker) Reset(d time.Duration) {
+	if d <= 0 {
+		panic("clock: non-positive interval for Ticker.Reset")
+	}
+	t.w.Reset(d)
+}
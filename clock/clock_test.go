@@ -0,0 +1,133 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+var epoch = time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+
+func fired(ch <-chan time.Time) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
+
+func TestFakeNonPositiveFiresImmediately(t *testing.T) {
+	clk := NewFakeClock(epoch)
+
+	for _, d := range []time.Duration{0, -time.Second} {
+		if !fired(clk.After(d)) {
+			t.Errorf("After(%s) did not fire without Advance", d)
+		}
+		if !fired(clk.NewTimer(d).C()) {
+			t.Errorf("NewTimer(%s) did not fire without Advance", d)
+		}
+		if !returnsPromptly(func() { clk.Sleep(d) }) {
+			t.Errorf("Sleep(%s) blocked", d)
+		}
+	}
+}
+
+// returnsPromptly reports whether fn returns within a second of real time.
+func returnsPromptly(fn func()) bool {
+	done := make(chan struct{})
+	go func() {
+		fn()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return true
+	case <-time.After(time.Second):
+		return false
+	}
+}
+
+func TestFakeResetToZeroFiresImmediately(t *testing.T) {
+	clk := NewFakeClock(epoch)
+	timer := clk.NewTimer(time.Hour)
+
+	if !timer.Reset(0) {
+		t.Error("Reset() = false for a pending timer")
+	}
+	if !fired(timer.C()) {
+		t.Error("Reset(0) did not fire without Advance")
+	}
+	if timer.Stop() {
+		t.Error("Stop() = true for a timer that already fired")
+	}
+}
+
+func TestFakeAdvanceFiresInOrder(t *testing.T) {
+	clk := NewFakeClock(epoch)
+	late := clk.NewTimer(2 * time.Second)
+	early := clk.NewTimer(time.Second)
+
+	clk.Advance(1500 * time.Millisecond)
+	if at, ok := <-early.C(); !ok || !at.Equal(epoch.Add(time.Second)) {
+		t.Errorf("early fired at %s, want %s", at, epoch.Add(time.Second))
+	}
+	if fired(late.C()) {
+		t.Error("late fired before its deadline")
+	}
+
+	clk.Advance(time.Second)
+	if !fired(late.C()) {
+		t.Error("late did not fire")
+	}
+	if got := clk.Now(); !got.Equal(epoch.Add(2500 * time.Millisecond)) {
+		t.Errorf("Now() = %s, want %s", got, epoch.Add(2500*time.Millisecond))
+	}
+}
+
+func TestFakeTickerDropsUnreadTicks(t *testing.T) {
+	clk := NewFakeClock(epoch)
+	ticker := clk.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	clk.Advance(3 * time.Second)
+	if !fired(ticker.C()) {
+		t.Fatal("ticker did not tick")
+	}
+	if fired(ticker.C()) {
+		t.Error("ticker buffered more than one tick")
+	}
+}
+
+func TestFakeBlockUntil(t *testing.T) {
+	clk := NewFakeClock(epoch)
+	woke := make(chan struct{})
+	go func() {
+		clk.Sleep(time.Minute)
+		close(woke)
+	}()
+
+	clk.BlockUntil(1)
+	clk.Advance(time.Minute)
+	if !returnsPromptly(func() { <-woke }) {
+		t.Fatal("sleeper did not wake after Advance")
+	}
+}
+
+func TestFakeStopDropsUnreadTick(t *testing.T) {
+	clk := NewFakeClock(epoch)
+	timer := clk.NewTimer(time.Second)
+	ticker := clk.NewTicker(time.Second)
+
+	clk.Advance(time.Second) // Both fire, nobody reads
+	if timer.Stop() {
+		t.Error("Stop() = true for a timer that already fired")
+	}
+	ticker.Stop()
+
+	if fired(timer.C()) {
+		t.Error("stopped timer delivered a stale value")
+	}
+	if fired(ticker.C()) {
+		t.Error("stopped ticker delivered a stale tick")
+	}
+}
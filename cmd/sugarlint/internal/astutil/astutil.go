@@ -0,0 +1,96 @@
+// Package astutil holds the syntax and type helpers the sugarlint passes
+// share.
+package astutil
+
+import (
+	"go/ast"
+	"go/types"
+)
+
+// InspectStack is ast.Inspect with the ancestors of each node, outermost
+// first, not including the node itself.
+func InspectStack(root ast.Node, f func(n ast.Node, stack []ast.Node) bool) {
+	var stack []ast.Node
+	ast.Inspect(root, func(n ast.Node) bool {
+		if n == nil {
+			stack = stack[:len(stack)-1]
+			return true
+		}
+		if !f(n, stack) {
+			return false
+		}
+		stack = append(stack, n)
+		return true
+	})
+}
+
+// IsBuiltin reports whether call calls the predeclared function name.
+func IsBuiltin(info *types.Info, call *ast.CallExpr, name string) bool {
+	id, ok := ast.Unparen(call.Fun).(*ast.Ident)
+	if !ok {
+		return false
+	}
+	b, ok := info.Uses[id].(*types.Builtin)
+	return ok && b.Name() == name
+}
+
+// IsPkgFunc reports whether call calls the package-level function path.name.
+func IsPkgFunc(info *types.Info, call *ast.CallExpr, path, name string) bool {
+	sel, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	fn, ok := info.Uses[sel.Sel].(*types.Func)
+	return ok && fn.Pkg() != nil && fn.Pkg().Path() == path && fn.Name() == name
+}
+
+// VarOf returns the variable e names, or nil.
+func VarOf(info *types.Info, e ast.Expr) *types.Var {
+	id, ok := ast.Unparen(e).(*ast.Ident)
+	if !ok {
+		return nil
+	}
+	obj := info.Uses[id]
+	if obj == nil {
+		obj = info.Defs[id]
+	}
+	v, _ := obj.(*types.Var)
+	return v
+}
+
+// EnclosingFunc returns the innermost *ast.FuncDecl or *ast.FuncLit on the
+// stack and its index, or nil and -1.
+func EnclosingFunc(stack []ast.Node) (ast.Node, int) {
+	for i := len(stack) - 1; i >= 0; i-- {
+		switch stack[i].(type) {
+		case *ast.FuncDecl, *ast.FuncLit:
+			return stack[i], i
+		}
+	}
+	return nil, -1
+}
+
+// FuncBody returns the body of a *ast.FuncDecl or *ast.FuncLit.
+func FuncBody(fn ast.Node) *ast.BlockStmt {
+	switch fn := fn.(type) {
+	case *ast.FuncDecl:
+		return fn.Body
+	case *ast.FuncLit:
+		return fn.Body
+	}
+	return nil
+}
+
+// InLoop reports whether the stack has a for or range statement inside
+// the innermost function.
+func InLoop(stack []ast.Node) bool {
+	for i := len(stack) - 1; i >= 0; i-- {
+		switch stack[i].(type) {
+		case *ast.ForStmt, *ast.RangeStmt:
+			return true
+		case *ast.FuncDecl, *ast.FuncLit:
+			return false
+		}
+	}
+	return false
+}
@@ -0,0 +1,65 @@
+// Package ignore lets source code silence sugarlint findings that are
+// deliberate, such as a demo of the panic the pitfall causes:
+//
+//	//sugarlint:ignore nilmap shows the nil-map panic
+//	m["key"] = 1
+//
+// The directive names one or more analyzers, comma-separated, followed by
+// the reason. It applies to its own line, so it can trail the statement,
+// and to the line after it.
+package ignore
+
+import (
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+const directive = "//sugarlint:ignore "
+
+// Wrap returns a copy of a whose diagnostics are dropped on lines that a
+// //sugarlint:ignore directive naming a covers.
+func Wrap(a *analysis.Analyzer) *analysis.Analyzer {
+	wrapped := *a
+	wrapped.Run = func(pass *analysis.Pass) (any, error) {
+		ignored := lines(pass, a.Name)
+		inner := *pass
+		inner.Report = func(d analysis.Diagnostic) {
+			pos := pass.Fset.Position(d.Pos)
+			if !ignored[line{pos.Filename, pos.Line}] {
+				pass.Report(d)
+			}
+		}
+		return a.Run(&inner)
+	}
+	return &wrapped
+}
+
+type line struct {
+	file string
+	n    int
+}
+
+// lines returns the lines covered by directives naming the analyzer.
+func lines(pass *analysis.Pass, name string) map[line]bool {
+	out := map[line]bool{}
+	for _, f := range pass.Files {
+		for _, group := range f.Comments {
+			for _, c := range group.List {
+				rest, ok := strings.CutPrefix(c.Text, directive)
+				if !ok {
+					continue
+				}
+				names, _, _ := strings.Cut(strings.TrimSpace(rest), " ")
+				if !slices.Contains(strings.Split(names, ","), name) {
+					continue
+				}
+				pos := pass.Fset.Position(c.Slash)
+				out[line{pos.Filename, pos.Line}] = true
+				out[line{pos.Filename, pos.Line + 1}] = true
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,14 @@
+package ignore_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"sugar/cmd/sugarlint/internal/ignore"
+	"sugar/cmd/sugarlint/passes/nilmap"
+)
+
+func TestWrap(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), ignore.Wrap(nilmap.Analyzer), "ignore")
+}
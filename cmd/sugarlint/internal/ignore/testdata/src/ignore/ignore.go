@@ -0,0 +1,25 @@
+package ignore
+
+func sameLine() {
+	var m map[string]int
+	m["a"] = 1 //sugarlint:ignore nilmap deliberate panic demo
+}
+
+func lineAbove() {
+	var m map[string]int
+	//sugarlint:ignore closedsend,nilmap deliberate panic demo
+	m["a"] = 1
+}
+
+func otherAnalyzer() {
+	var m map[string]int
+	//sugarlint:ignore recoverdefer names a different analyzer
+	m["a"] = 1 // want `assignment to entry in nil map m`
+}
+
+func tooFarAbove() {
+	var m map[string]int
+	//sugarlint:ignore nilmap only covers the next line
+
+	m["a"] = 1 // want `assignment to entry in nil map m`
+}
@@ -0,0 +1,52 @@
+// Sugarlint checks Go code for the pitfalls the examples in this
+// repository document in their comments:
+//
+//	recoverdefer  recover() called outside a deferred function
+//	nilmap        writes to a map declared with var and never made
+//	closedsend    sends on (or closing) a channel after close
+//	timeafter     time.After in a select inside a loop
+//	rangecopy     writes to a range value copy that are never read
+//	fanin         a fan-in output channel that is never closed
+//
+// Usage:
+//
+//	go run ./cmd/sugarlint ./...
+//	go run ./cmd/sugarlint -fix ./...        # apply nilmap and rangecopy fixes
+//	go run ./cmd/sugarlint -nilmap ./...     # run only the named analyzers
+//
+// It exits non-zero if anything is found, so it can gate a merge. Findings
+// that are deliberate - a demo of the panic a pitfall causes - are
+// silenced with a directive on the line or the line above:
+//
+//	//sugarlint:ignore nilmap shows the nil-map panic
+package main
+
+import (
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/multichecker"
+
+	"sugar/cmd/sugarlint/internal/ignore"
+	"sugar/cmd/sugarlint/passes/closedsend"
+	"sugar/cmd/sugarlint/passes/fanin"
+	"sugar/cmd/sugarlint/passes/nilmap"
+	"sugar/cmd/sugarlint/passes/recoverdefer"
+	"sugar/cmd/sugarlint/passes/timeafter"
+	"sugar/range/update/rangecopy"
+)
+
+var analyzers = []*analysis.Analyzer{
+	recoverdefer.Analyzer,
+	nilmap.Analyzer,
+	closedsend.Analyzer,
+	timeafter.Analyzer,
+	rangecopy.Analyzer,
+	fanin.Analyzer,
+}
+
+func main() {
+	wrapped := make([]*analysis.Analyzer, len(analyzers))
+	for i, a := range analyzers {
+		wrapped[i] = ignore.Wrap(a)
+	}
+	multichecker.Main(wrapped...)
+}
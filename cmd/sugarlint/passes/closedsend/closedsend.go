@@ -0,0 +1,107 @@
+// Package closedsend defines an Analyzer that reports sends on a channel,
+// or a second close, after close(ch).
+//
+// Sending on a closed channel panics, and so does closing it again. The
+// check covers straight-line code: a close followed, in the same block, by
+// a send or close on the same variable with no reassignment in between.
+package closedsend
+
+import (
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+
+	"sugar/cmd/sugarlint/internal/astutil"
+)
+
+var Analyzer = &analysis.Analyzer{
+	Name: "closedsend",
+	Doc: `report sends on a channel, or a second close, after close(ch)
+
+Sending on a closed channel panics, and so does closing it again. This
+checks straight-line code: a close followed, in the same block, by a send
+or close on the same variable with no reassignment in between.`,
+	URL: "https://go.dev/ref/spec#Close",
+	Run: run,
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	for _, file := range pass.Files {
+		ast.Inspect(file, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.BlockStmt:
+				checkAfterClose(pass, n.List)
+			case *ast.CaseClause:
+				checkAfterClose(pass, n.Body)
+			case *ast.CommClause:
+				checkAfterClose(pass, n.Body)
+			}
+			return true
+		})
+	}
+	return nil, nil
+}
+
+// closeOf returns the variable closed by n if it is close(v).
+func closeOf(info *types.Info, n ast.Node) *types.Var {
+	var call *ast.CallExpr
+	switch n := n.(type) {
+	case *ast.ExprStmt:
+		call, _ = n.X.(*ast.CallExpr)
+	case *ast.CallExpr:
+		call = n
+	}
+	if call == nil || !astutil.IsBuiltin(info, call, "close") || len(call.Args) != 1 {
+		return nil
+	}
+	return astutil.VarOf(info, call.Args[0])
+}
+
+func checkAfterClose(pass *analysis.Pass, list []ast.Stmt) {
+	info := pass.TypesInfo
+	for i, stmt := range list {
+		ch := closeOf(info, stmt)
+		if ch == nil {
+			continue
+		}
+		line := pass.Fset.Position(stmt.Pos()).Line
+
+	rest:
+		for _, next := range list[i+1:] {
+			reassigned := false
+			ast.Inspect(next, func(n ast.Node) bool {
+				if as, ok := n.(*ast.AssignStmt); ok {
+					for _, lhs := range as.Lhs {
+						if astutil.VarOf(info, lhs) == ch {
+							reassigned = true
+						}
+					}
+				}
+				return !reassigned
+			})
+			if reassigned {
+				break
+			}
+
+			closedAgain := false
+			ast.Inspect(next, func(n ast.Node) bool {
+				switch n := n.(type) {
+				case *ast.SendStmt:
+					if astutil.VarOf(info, n.Chan) == ch {
+						pass.Reportf(n.Pos(), "send on %s after close(%s) on line %d panics", ch.Name(), ch.Name(), line)
+					}
+				case *ast.CallExpr:
+					if closeOf(info, n) == ch {
+						pass.Reportf(n.Pos(), "close of %s, already closed on line %d, panics", ch.Name(), line)
+						closedAgain = true
+					}
+				}
+				return !closedAgain
+			})
+			if closedAgain {
+				break rest // The second close reports what follows it
+			}
+		}
+	}
+}
@@ -0,0 +1,13 @@
+package closedsend_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"sugar/cmd/sugarlint/passes/closedsend"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), closedsend.Analyzer, "closedsend")
+}
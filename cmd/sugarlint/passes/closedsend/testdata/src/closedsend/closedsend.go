@@ -0,0 +1,64 @@
+package closedsend
+
+func sendAfterClose() {
+	ch := make(chan int, 1)
+	close(ch)
+	ch <- 1 // want `send on ch after close\(ch\) on line \d+ panics`
+}
+
+func doubleClose() {
+	ch := make(chan int)
+	close(ch)
+	close(ch) // want `close of ch, already closed on line \d+, panics`
+}
+
+func inSelect(done chan struct{}) {
+	out := make(chan int, 1)
+	close(out)
+	for {
+		select {
+		case out <- 1: // want `send on out after close\(out\)`
+		case <-done:
+			return
+		}
+	}
+}
+
+func inGoroutine() {
+	ch := make(chan int)
+	close(ch)
+	go func() {
+		ch <- 1 // want `send on ch after close\(ch\)`
+	}()
+}
+
+// --- No diagnostics below ---
+
+func receiveAfterClose() int {
+	ch := make(chan int, 1)
+	ch <- 1
+	close(ch)
+	return <-ch + <-ch // Receives drain the buffer, then yield zero values
+}
+
+func closeOnOneBranch(stop bool) {
+	ch := make(chan int, 1)
+	if stop {
+		close(ch)
+		return
+	}
+	ch <- 1
+}
+
+func replaced() {
+	ch := make(chan int, 1)
+	close(ch)
+	ch = make(chan int, 1)
+	ch <- 1
+}
+
+func deferredClose() {
+	ch := make(chan int, 1)
+	defer close(ch)
+	ch <- 1
+}
@@ -0,0 +1,118 @@
+// Package fanin defines an Analyzer that reports fan-in output channels
+// that are never closed.
+//
+// A function that makes a channel, starts several goroutines that send on
+// it, and returns it must close it once they are done - usually after a
+// sync.WaitGroup Wait - or a receiver ranging over it blocks forever.
+package fanin
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+
+	"sugar/cmd/sugarlint/internal/astutil"
+)
+
+var Analyzer = &analysis.Analyzer{
+	Name: "fanin",
+	Doc: `report fan-in output channels that are never closed
+
+A function that makes a channel, starts several goroutines that send on
+it, and returns it must close it once they are done - usually after a
+sync.WaitGroup Wait - or a receiver ranging over it blocks forever.`,
+	URL: "https://go.dev/blog/pipelines",
+	Run: run,
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	for _, file := range pass.Files {
+		ast.Inspect(file, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.FuncDecl:
+				if n.Body != nil {
+					check(pass, n.Body)
+				}
+			case *ast.FuncLit:
+				check(pass, n.Body)
+			}
+			return true
+		})
+	}
+	return nil, nil
+}
+
+// check looks at the channels made directly in body.
+func check(pass *analysis.Pass, body *ast.BlockStmt) {
+	info := pass.TypesInfo
+	astutil.InspectStack(body, func(n ast.Node, stack []ast.Node) bool {
+		if _, ok := n.(*ast.FuncLit); ok {
+			return false // Checked on its own
+		}
+		as, ok := n.(*ast.AssignStmt)
+		if !ok || as.Tok != token.DEFINE || len(as.Lhs) != 1 || len(as.Rhs) != 1 {
+			return true
+		}
+		call, ok := as.Rhs[0].(*ast.CallExpr)
+		if !ok || !astutil.IsBuiltin(info, call, "make") {
+			return true
+		}
+		out := astutil.VarOf(info, as.Lhs[0])
+		if out == nil {
+			return true
+		}
+		if _, isChan := out.Type().Underlying().(*types.Chan); !isChan {
+			return true
+		}
+		if unclosed(pass, body, as.Lhs[0].(*ast.Ident), out) {
+			pass.Reportf(as.Pos(), "fan-in output %s is never closed: a range over it blocks forever once the inputs are drained; close it after the senders finish (sync.WaitGroup)", out.Name())
+		}
+		return true
+	})
+}
+
+func unclosed(pass *analysis.Pass, body *ast.BlockStmt, def *ast.Ident, out *types.Var) bool {
+	info := pass.TypesInfo
+	returned, closed, escaped := false, false, false
+	senders := map[*ast.GoStmt]bool{}
+	inLoopSender := false
+
+	astutil.InspectStack(body, func(n ast.Node, stack []ast.Node) bool {
+		id, ok := n.(*ast.Ident)
+		if !ok || id == def || info.Uses[id] != out {
+			return true
+		}
+		switch parent := stack[len(stack)-1].(type) {
+		case *ast.SendStmt:
+			if parent.Chan != id {
+				escaped = true // Sent as a value on another channel
+				break
+			}
+			for i := len(stack) - 1; i >= 0; i-- {
+				if g, ok := stack[i].(*ast.GoStmt); ok {
+					senders[g] = true
+					inLoopSender = inLoopSender || astutil.InLoop(stack[:i])
+					break
+				}
+			}
+		case *ast.ReturnStmt:
+			if fn, _ := astutil.EnclosingFunc(stack); fn == nil { // Not inside a nested function literal
+				returned = true
+			} else {
+				escaped = true
+			}
+		case *ast.CallExpr:
+			if astutil.IsBuiltin(info, parent, "close") {
+				closed = true
+			} else {
+				escaped = true // The callee may close it
+			}
+		default:
+			escaped = true
+		}
+		return true
+	})
+	return returned && !closed && !escaped && (len(senders) > 1 || inLoopSender)
+}
@@ -0,0 +1,13 @@
+package fanin_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"sugar/cmd/sugarlint/passes/fanin"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), fanin.Analyzer, "fanin")
+}
@@ -0,0 +1,90 @@
+package fanin
+
+import "sync"
+
+func merge(channels ...<-chan int) <-chan int {
+	out := make(chan int) // want `fan-in output out is never closed`
+	for _, ch := range channels {
+		go func() {
+			for v := range ch {
+				out <- v
+			}
+		}()
+	}
+	return out
+}
+
+func pair(a, b <-chan string) <-chan string {
+	out := make(chan string) // want `fan-in output out is never closed`
+	go func() {
+		for v := range a {
+			out <- v
+		}
+	}()
+	go func() {
+		for v := range b {
+			out <- v
+		}
+	}()
+	return out
+}
+
+func literal() {
+	fanIn := func(channels ...<-chan int) <-chan int {
+		out := make(chan int) // want `fan-in output out is never closed`
+		for _, ch := range channels {
+			go func() {
+				for val := range ch {
+					out <- val
+				}
+			}()
+		}
+		return out
+	}
+	_ = fanIn
+}
+
+// --- No diagnostics below ---
+
+func mergeClosed(channels ...<-chan int) <-chan int {
+	out := make(chan int)
+	var wg sync.WaitGroup
+	for _, ch := range channels {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for v := range ch {
+				out <- v
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+	return out
+}
+
+// A single endless sender is a generator, not a fan-in.
+func generator() <-chan int {
+	out := make(chan int)
+	go func() {
+		for i := 0; ; i++ {
+			out <- i
+		}
+	}()
+	return out
+}
+
+func handedOff(channels []<-chan int, closeWhenDone func(chan int)) <-chan int {
+	out := make(chan int)
+	for _, ch := range channels {
+		go func() {
+			for v := range ch {
+				out <- v
+			}
+		}()
+	}
+	closeWhenDone(out) // The callee may close it
+	return out
+}
@@ -0,0 +1,148 @@
+// Package nilmap defines an Analyzer that reports writes to a map that is
+// declared with var and never made.
+//
+// "var m map[K]V" declares a nil map. Reading it returns zero values, but
+// m[k] = v panics with "assignment to entry in nil map". The suggested fix
+// initializes the declaration:
+//
+//	var m = make(map[K]V)
+package nilmap
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+
+	"sugar/cmd/sugarlint/internal/astutil"
+)
+
+var Analyzer = &analysis.Analyzer{
+	Name: "nilmap",
+	Doc: `report writes to a map declared with var and never made
+
+"var m map[K]V" declares a nil map. Reading it returns zero values, but
+m[k] = v panics with "assignment to entry in nil map". The suggested fix
+initializes the declaration with make.`,
+	URL: "https://go.dev/blog/maps",
+	Run: run,
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	for _, file := range pass.Files {
+		astutil.InspectStack(file, func(n ast.Node, stack []ast.Node) bool {
+			decl, ok := n.(*ast.DeclStmt)
+			if !ok {
+				return true
+			}
+			gen, ok := decl.Decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.VAR {
+				return true
+			}
+			fn, _ := astutil.EnclosingFunc(stack)
+			for _, spec := range gen.Specs {
+				spec := spec.(*ast.ValueSpec)
+				if len(spec.Values) > 0 || spec.Type == nil {
+					continue
+				}
+				for _, name := range spec.Names {
+					v, ok := pass.TypesInfo.Defs[name].(*types.Var)
+					if !ok {
+						continue
+					}
+					if _, isMap := v.Type().Underlying().(*types.Map); isMap {
+						check(pass, astutil.FuncBody(fn), spec, v)
+					}
+				}
+			}
+			return true
+		})
+	}
+	return nil, nil
+}
+
+func check(pass *analysis.Pass, body *ast.BlockStmt, spec *ast.ValueSpec, v *types.Var) {
+	type write struct {
+		stmt  ast.Stmt
+		loops []ast.Node
+	}
+	var inits []token.Pos // m = ..., &m
+	var writes []write
+
+	isM := func(e ast.Expr) bool { return astutil.VarOf(pass.TypesInfo, e) == v }
+	isEntry := func(e ast.Expr) bool {
+		ix, ok := ast.Unparen(e).(*ast.IndexExpr)
+		return ok && isM(ix.X)
+	}
+	loops := func(stack []ast.Node) []ast.Node {
+		var out []ast.Node
+		for _, s := range stack {
+			switch s.(type) {
+			case *ast.ForStmt, *ast.RangeStmt:
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+
+	astutil.InspectStack(body, func(n ast.Node, stack []ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			for _, lhs := range n.Lhs {
+				if isM(lhs) {
+					inits = append(inits, n.Pos())
+				} else if isEntry(lhs) {
+					writes = append(writes, write{n, loops(stack)})
+				}
+			}
+		case *ast.IncDecStmt:
+			if isEntry(n.X) {
+				writes = append(writes, write{n, loops(stack)})
+			}
+		case *ast.UnaryExpr:
+			if n.Op == token.AND && isM(n.X) {
+				inits = append(inits, n.Pos())
+			}
+		}
+		return true
+	})
+
+	fixed := false
+	for _, w := range writes {
+		safe := false
+		for _, pos := range inits {
+			if pos < w.stmt.Pos() {
+				safe = true
+			}
+			for _, loop := range w.loops {
+				if pos >= loop.Pos() && pos < loop.End() {
+					safe = true // Made on an earlier iteration
+				}
+			}
+		}
+		if safe {
+			continue
+		}
+
+		d := analysis.Diagnostic{
+			Pos:     w.stmt.Pos(),
+			End:     w.stmt.End(),
+			Message: fmt.Sprintf("assignment to entry in nil map %s panics: it is declared with var and never made", v.Name()),
+		}
+		if !fixed && len(spec.Names) == 1 {
+			// var m map[K]V  ->  var m = make(map[K]V)
+			d.SuggestedFixes = []analysis.SuggestedFix{{
+				Message: "Initialize " + v.Name() + " with make",
+				TextEdits: []analysis.TextEdit{{
+					Pos:     spec.Names[0].End(),
+					End:     spec.Type.End(),
+					NewText: []byte(" = make(" + types.ExprString(spec.Type) + ")"),
+				}},
+			}}
+			fixed = true
+		}
+		pass.Report(d)
+	}
+}
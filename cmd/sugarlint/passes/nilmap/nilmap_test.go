@@ -0,0 +1,13 @@
+package nilmap_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"sugar/cmd/sugarlint/passes/nilmap"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.RunWithSuggestedFixes(t, analysistest.TestData(), nilmap.Analyzer, "nilmap")
+}
@@ -0,0 +1,49 @@
+package nilmap
+
+import "fmt"
+
+func whatHappened() {
+	var m map[string]int
+
+	val := m["key"] // Reading a nil map is fine
+	fmt.Println(val)
+
+	m["key"] = 1 // want `assignment to entry in nil map m panics`
+}
+
+func counting(words []string) map[string]int {
+	var counts map[string]int
+	for _, w := range words {
+		counts[w]++ // want `assignment to entry in nil map counts panics`
+	}
+	return counts
+}
+
+// --- No diagnostics below ---
+
+func made() {
+	m := make(map[string]int)
+	m["a"] = 1
+}
+
+func assignedBeforeWrite() {
+	var m map[string]int
+	m = map[string]int{}
+	m["a"] = 1
+}
+
+func lazyInLoop(words []string) {
+	var seen map[string]bool
+	for _, w := range words {
+		if seen == nil {
+			seen = make(map[string]bool)
+		}
+		seen[w] = true
+	}
+}
+
+func throughPointer(fill func(*map[string]int)) {
+	var m map[string]int
+	fill(&m)
+	m["a"] = 1
+}
@@ -0,0 +1,107 @@
+// Package recoverdefer defines an Analyzer that reports recover() calls
+// that can never stop a panic.
+//
+// recover only returns the panic value when it is called directly by a
+// deferred function. Called anywhere else - inline, in a goroutine, or in
+// a helper that a deferred function calls - it returns nil:
+//
+//	if r := recover(); r != nil { // never true
+//		...
+//	}
+package recoverdefer
+
+import (
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+
+	"sugar/cmd/sugarlint/internal/astutil"
+)
+
+var Analyzer = &analysis.Analyzer{
+	Name: "recoverdefer",
+	Doc: `report recover() calls that can never stop a panic
+
+recover only returns the panic value when it is called directly by a
+deferred function. Called anywhere else - inline, in a goroutine, or in a
+helper that a deferred function calls - it returns nil.`,
+	URL: "https://go.dev/ref/spec#Handling_panics",
+	Run: run,
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	// Functions and function variables deferred by name: defer f(), defer x.m()
+	deferred := map[types.Object]bool{}
+	for _, file := range pass.Files {
+		ast.Inspect(file, func(n ast.Node) bool {
+			if d, ok := n.(*ast.DeferStmt); ok {
+				if obj := calleeObject(pass.TypesInfo, d.Call.Fun); obj != nil {
+					deferred[obj] = true
+				}
+			}
+			return true
+		})
+	}
+
+	for _, file := range pass.Files {
+		astutil.InspectStack(file, func(n ast.Node, stack []ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if ok && astutil.IsBuiltin(pass.TypesInfo, call, "recover") && !calledDeferred(pass, stack, deferred) {
+				pass.Reportf(call.Pos(), "recover() only stops a panic when called directly by a deferred function; here it always returns nil")
+			}
+			return true
+		})
+	}
+	return nil, nil
+}
+
+func calleeObject(info *types.Info, fun ast.Expr) types.Object {
+	var obj types.Object
+	switch f := ast.Unparen(fun).(type) {
+	case *ast.Ident:
+		obj = info.Uses[f]
+	case *ast.SelectorExpr:
+		obj = info.Uses[f.Sel]
+	}
+	if fn, ok := obj.(*types.Func); ok {
+		return fn.Origin()
+	}
+	return obj
+}
+
+// calledDeferred reports whether the function enclosing a recover call
+// (whose ancestors are stack) is itself deferred.
+func calledDeferred(pass *analysis.Pass, stack []ast.Node, deferred map[types.Object]bool) bool {
+	fn, i := astutil.EnclosingFunc(stack)
+	switch fn := fn.(type) {
+	case *ast.FuncDecl:
+		if deferred[pass.TypesInfo.Defs[fn.Name]] {
+			return true
+		}
+		// Importers may defer an exported function; only main is closed
+		return pass.Pkg.Name() != "main" && fn.Name.IsExported()
+
+	case *ast.FuncLit:
+		switch parent := stack[i-1].(type) {
+		case *ast.CallExpr: // defer func() { ... }()
+			d, ok := stack[i-2].(*ast.DeferStmt)
+			return ok && d.Call == parent && parent.Fun == fn
+		case *ast.AssignStmt: // catch := func() { ... }; defer catch()
+			for j, rhs := range parent.Rhs {
+				if rhs == fn && j < len(parent.Lhs) {
+					if v := astutil.VarOf(pass.TypesInfo, parent.Lhs[j]); v != nil && deferred[v] {
+						return true
+					}
+				}
+			}
+		case *ast.ValueSpec: // var catch = func() { ... }
+			for j, val := range parent.Values {
+				if val == fn && j < len(parent.Names) && deferred[pass.TypesInfo.Defs[parent.Names[j]]] {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,13 @@
+package recoverdefer_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"sugar/cmd/sugarlint/passes/recoverdefer"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), recoverdefer.Analyzer, "recoverdefer")
+}
@@ -0,0 +1,70 @@
+package recoverdefer
+
+import "fmt"
+
+func outsideDefer() {
+	if r := recover(); r != nil { // want `recover\(\) only stops a panic when called directly by a deferred function`
+		fmt.Println("This won't catch anything")
+	}
+	panic("boom")
+}
+
+func helper() {
+	if r := recover(); r != nil { // want `recover\(\) only stops a panic`
+		fmt.Println(r)
+	}
+}
+
+func indirect() {
+	defer func() {
+		helper() // The deferred function must call recover itself, not a callee
+	}()
+	panic("boom")
+}
+
+func inGoroutine() {
+	go func() {
+		recover() // want `recover\(\) only stops a panic`
+	}()
+}
+
+// --- No diagnostics below ---
+
+func deferredLiteral() {
+	defer func() {
+		if r := recover(); r != nil {
+			fmt.Println(r)
+		}
+	}()
+	panic("boom")
+}
+
+func handle() {
+	if r := recover(); r != nil {
+		fmt.Println(r)
+	}
+}
+
+func deferredByName() {
+	defer handle()
+	panic("boom")
+}
+
+type guard struct{}
+
+func (guard) catch() { recover() }
+
+func deferredMethod() {
+	var g guard
+	defer g.catch()
+	panic("boom")
+}
+
+func deferredVariable() {
+	catch := func() { recover() }
+	defer catch()
+	panic("boom")
+}
+
+// Recover is exported, so importing packages may defer it.
+func Recover() { recover() }
@@ -0,0 +1,70 @@
+package timeafter
+
+import "time"
+
+func selectInLoop(ch <-chan int) {
+	for {
+		select {
+		case v, ok := <-ch:
+			if !ok {
+				return
+			}
+			_ = v
+		case <-time.After(time.Second): // want `time.After in a select inside a loop starts a new timer on every iteration`
+			return
+		}
+	}
+}
+
+func rangeLoop(chs []chan int) {
+	for _, ch := range chs {
+		select {
+		case <-ch:
+		case <-time.After(10 * time.Millisecond): // want `time.After in a select inside a loop`
+		}
+	}
+}
+
+// --- No diagnostics below ---
+
+func once(ch <-chan int) {
+	select {
+	case <-ch:
+	case <-time.After(time.Second):
+	}
+}
+
+func overallDeadline(ch <-chan int) {
+	timeout := time.After(time.Second) // One timer for the whole loop
+	for {
+		select {
+		case <-ch:
+		case <-timeout:
+			return
+		}
+	}
+}
+
+func reusedTimer(ch <-chan int) {
+	t := time.NewTimer(time.Second)
+	defer t.Stop()
+	for {
+		select {
+		case <-ch:
+			t.Reset(time.Second)
+		case <-t.C:
+			return
+		}
+	}
+}
+
+func goroutinePerIteration(chs []chan int) {
+	for _, ch := range chs {
+		go func() {
+			select {
+			case <-ch:
+			case <-time.After(time.Second): // Runs once per goroutine
+			}
+		}()
+	}
+}
@@ -0,0 +1,51 @@
+// Package timeafter defines an Analyzer that reports time.After in a
+// select inside a loop.
+//
+// Every evaluation of time.After creates a new timer, so a receive loop
+// with "case <-time.After(d)" allocates one per iteration and restarts the
+// timeout each time. Create one timer before the loop and Reset it, or
+// call time.After once if the deadline is for the whole loop.
+package timeafter
+
+import (
+	"go/ast"
+
+	"golang.org/x/tools/go/analysis"
+
+	"sugar/cmd/sugarlint/internal/astutil"
+)
+
+var Analyzer = &analysis.Analyzer{
+	Name: "timeafter",
+	Doc: `report time.After in a select inside a loop
+
+Every evaluation of time.After creates a new timer, so a receive loop with
+"case <-time.After(d)" allocates one per iteration and restarts the
+timeout each time. Create one timer before the loop and Reset it, or call
+time.After once if the deadline is for the whole loop.`,
+	URL: "https://pkg.go.dev/time#After",
+	Run: run,
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	for _, file := range pass.Files {
+		astutil.InspectStack(file, func(n ast.Node, stack []ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || !astutil.IsPkgFunc(pass.TypesInfo, call, "time", "After") {
+				return true
+			}
+			inComm := false
+			for i := len(stack) - 1; i >= 0; i-- {
+				if cc, ok := stack[i].(*ast.CommClause); ok {
+					inComm = cc.Comm != nil && call.Pos() >= cc.Comm.Pos() && call.End() <= cc.Comm.End()
+					break
+				}
+			}
+			if inComm && astutil.InLoop(stack) {
+				pass.Reportf(call.Pos(), "time.After in a select inside a loop starts a new timer on every iteration; create one timer before the loop and Reset it")
+			}
+			return true
+		})
+	}
+	return nil, nil
+}
@@ -0,0 +1,13 @@
+package timeafter_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"sugar/cmd/sugarlint/passes/timeafter"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), timeafter.Analyzer, "timeafter")
+}
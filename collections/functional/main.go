@@ -0,0 +1,423 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"iter"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+)
+
+// --- 1. Slice Helpers ---
+
+// Map returns f applied to every element.
+func Map[T, U any](s []T, f func(T) U) []U {
+	out := make([]U, len(s))
+	for i, v := range s {
+		out[i] = f(v)
+	}
+	return out
+}
+
+// Filter returns the elements for which keep is true.
+func Filter[T any](s []T, keep func(T) bool) []T {
+	var out []T
+	for _, v := range s {
+		if keep(v) {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// Reduce folds s into one value, starting from init.
+func Reduce[T, A any](s []T, init A, f func(A, T) A) A {
+	acc := init
+	for _, v := range s {
+		acc = f(acc, v)
+	}
+	return acc
+}
+
+// GroupBy buckets elements by key, keeping their order within each bucket.
+func GroupBy[T any, K comparable](s []T, key func(T) K) map[K][]T {
+	out := make(map[K][]T)
+	for _, v := range s {
+		k := key(v)
+		out[k] = append(out[k], v)
+	}
+	return out
+}
+
+// Partition splits s into the elements that match and those that don't.
+func Partition[T any](s []T, match func(T) bool) (yes, no []T) {
+	for _, v := range s {
+		if match(v) {
+			yes = append(yes, v)
+		} else {
+			no = append(no, v)
+		}
+	}
+	return yes, no
+}
+
+// Chunk splits s into slices of up to size elements. The chunks share
+// memory with s, but each is capped at its own length, so appending to one
+// copies instead of writing into the next chunk or past the end of s.
+func Chunk[T any](s []T, size int) [][]T {
+	if size <= 0 {
+		panic("collections: Chunk size must be positive")
+	}
+	out := make([][]T, 0, (len(s)+size-1)/size)
+	for size < len(s) {
+		out = append(out, s[:size:size]) // Full slice expression: appending to a chunk can't overwrite the next
+		s = s[size:]
+	}
+	if len(s) > 0 {
+		out = append(out, s[:len(s):len(s)]) // The caller may still own s[len(s):cap(s)]
+	}
+	return out
+}
+
+// Flatten joins nested slices into one.
+func Flatten[T any](s [][]T) []T {
+	n := 0
+	for _, inner := range s {
+		n += len(inner)
+	}
+	out := make([]T, 0, n)
+	for _, inner := range s {
+		out = append(out, inner...)
+	}
+	return out
+}
+
+// Pair holds one element from each side of a Zip.
+type Pair[A, B any] struct {
+	First  A
+	Second B
+}
+
+// Zip pairs up elements by index, stopping at the shorter slice.
+func Zip[A, B any](a []A, b []B) []Pair[A, B] {
+	n := min(len(a), len(b))
+	out := make([]Pair[A, B], n)
+	for i := range n {
+		out[i] = Pair[A, B]{a[i], b[i]}
+	}
+	return out
+}
+
+// Uniq drops repeated elements, keeping the first occurrence of each.
+func Uniq[T comparable](s []T) []T {
+	seen := make(map[T]struct{}, len(s))
+	var out []T
+	for _, v := range s {
+		if _, dup := seen[v]; !dup {
+			seen[v] = struct{}{}
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// --- 2. Map Helpers ---
+
+// KeyBy indexes s by key. If two elements share a key, the last one wins.
+func KeyBy[T any, K comparable](s []T, key func(T) K) map[K]T {
+	out := make(map[K]T, len(s))
+	for _, v := range s {
+		out[key(v)] = v
+	}
+	return out
+}
+
+// Invert swaps keys and values. If two keys share a value, which one
+// survives is unspecified, just like map iteration order.
+func Invert[K, V comparable](m map[K]V) map[V]K {
+	out := make(map[V]K, len(m))
+	for k, v := range m {
+		out[v] = k
+	}
+	return out
+}
+
+// SortedKeys returns the keys of m in ascending order, for deterministic output.
+func SortedKeys[K cmp.Ordered, V any](m map[K]V) []K {
+	return slices.Sorted(maps.Keys(m))
+}
+
+// --- 3. iter.Seq Counterparts ---
+//
+// The Seq versions are lazy: nothing runs until the result is ranged over,
+// and breaking out of the loop stops the whole pipeline. Chains of them
+// don't allocate the intermediate slices the eager versions do.
+
+// MapSeq is the lazy form of Map.
+func MapSeq[T, U any](seq iter.Seq[T], f func(T) U) iter.Seq[U] {
+	return func(yield func(U) bool) {
+		for v := range seq {
+			if !yield(f(v)) {
+				return
+			}
+		}
+	}
+}
+
+// FilterSeq is the lazy form of Filter.
+func FilterSeq[T any](seq iter.Seq[T], keep func(T) bool) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for v := range seq {
+			if keep(v) && !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// ReduceSeq consumes seq.
+func ReduceSeq[T, A any](seq iter.Seq[T], init A, f func(A, T) A) A {
+	acc := init
+	for v := range seq {
+		acc = f(acc, v)
+	}
+	return acc
+}
+
+// GroupBySeq consumes seq into groups.
+func GroupBySeq[T any, K comparable](seq iter.Seq[T], key func(T) K) map[K][]T {
+	out := make(map[K][]T)
+	for v := range seq {
+		k := key(v)
+		out[k] = append(out[k], v)
+	}
+	return out
+}
+
+// PartitionSeq consumes seq into matching and non-matching elements.
+func PartitionSeq[T any](seq iter.Seq[T], match func(T) bool) (yes, no []T) {
+	for v := range seq {
+		if match(v) {
+			yes = append(yes, v)
+		} else {
+			no = append(no, v)
+		}
+	}
+	return yes, no
+}
+
+// ChunkSeq yields batches of up to size elements. Each batch is a new
+// slice, so it may be kept after the loop moves on.
+func ChunkSeq[T any](seq iter.Seq[T], size int) iter.Seq[[]T] {
+	if size <= 0 {
+		panic("collections: ChunkSeq size must be positive")
+	}
+	return func(yield func([]T) bool) {
+		batch := make([]T, 0, size)
+		for v := range seq {
+			batch = append(batch, v)
+			if len(batch) == size {
+				if !yield(batch) {
+					return
+				}
+				batch = make([]T, 0, size)
+			}
+		}
+		if len(batch) > 0 {
+			yield(batch)
+		}
+	}
+}
+
+// FlattenSeq yields the elements of every inner slice in turn.
+func FlattenSeq[T any](seq iter.Seq[[]T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for inner := range seq {
+			for _, v := range inner {
+				if !yield(v) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// ZipSeq pairs up two sequences, stopping when either runs out.
+func ZipSeq[A, B any](a iter.Seq[A], b iter.Seq[B]) iter.Seq2[A, B] {
+	return func(yield func(A, B) bool) {
+		nextB, stop := iter.Pull(b)
+		defer stop()
+		for va := range a {
+			vb, ok := nextB()
+			if !ok || !yield(va, vb) {
+				return
+			}
+		}
+	}
+}
+
+// UniqSeq yields each distinct element the first time it appears.
+func UniqSeq[T comparable](seq iter.Seq[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		seen := make(map[T]struct{})
+		for v := range seq {
+			if _, dup := seen[v]; dup {
+				continue
+			}
+			seen[v] = struct{}{}
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// KeyBySeq yields each element under its key; collect it with maps.Collect.
+func KeyBySeq[T any, K comparable](seq iter.Seq[T], key func(T) K) iter.Seq2[K, T] {
+	return func(yield func(K, T) bool) {
+		for v := range seq {
+			if !yield(key(v), v) {
+				return
+			}
+		}
+	}
+}
+
+// InvertSeq swaps the two sides of a pair sequence, e.g. maps.All(m).
+func InvertSeq[K, V any](seq iter.Seq2[K, V]) iter.Seq2[V, K] {
+	return func(yield func(V, K) bool) {
+		for k, v := range seq {
+			if !yield(v, k) {
+				return
+			}
+		}
+	}
+}
+
+// SortedKeysSeq yields the keys of m in ascending order.
+func SortedKeysSeq[K cmp.Ordered, V any](m map[K]V) iter.Seq[K] {
+	return slices.Values(SortedKeys(m))
+}
+
+// --- 4. Examples ---
+
+type Employee struct {
+	Name string
+	Team string
+	Age  int
+}
+
+var employees = []Employee{
+	{"Alice", "Backend", 30},
+	{"Bob", "Backend", 25},
+	{"Charlie", "Frontend", 35},
+	{"David", "Frontend", 28},
+	{"Eve", "DevOps", 41},
+}
+
+func sliceHelpers() {
+	fmt.Println("\n=== 1. SLICE HELPERS ===")
+
+	numbers := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+
+	odd := Filter(numbers, func(n int) bool { return n%2 == 1 })
+	squares := Map(odd, func(n int) int { return n * n })
+	sum := Reduce(squares, 0, func(acc, n int) int { return acc + n })
+	fmt.Println("Odd:", odd, "Squares:", squares, "Sum:", sum)
+
+	small, big := Partition(numbers, func(n int) bool { return n <= 5 })
+	fmt.Println("Partition <= 5:", small, big)
+
+	chunks := Chunk(numbers, 4)
+	fmt.Println("Chunk(4):", chunks, "Flatten:", Flatten(chunks))
+
+	fmt.Println("Uniq:", Uniq([]string{"go", "rust", "go", "zig", "rust"}))
+
+	for _, p := range Zip([]string{"a", "b", "c"}, []int{1, 2}) {
+		fmt.Printf("Zip: %s=%d\n", p.First, p.Second)
+	}
+}
+
+func mapHelpers() {
+	fmt.Println("\n=== 2. MAP HELPERS (rangeOverMapOfSlices WITHOUT THE LOOPS) ===")
+
+	teams := GroupBy(employees, func(e Employee) string { return e.Team })
+	for _, team := range SortedKeys(teams) {
+		names := Map(teams[team], func(e Employee) string { return e.Name })
+		fmt.Printf("Team %s: %s\n", team, strings.Join(names, ", "))
+	}
+
+	byName := KeyBy(employees, func(e Employee) string { return e.Name })
+	fmt.Println("KeyBy name, Eve:", byName["Eve"])
+
+	codes := map[string]int{"ok": 200, "not_found": 404, "internal": 500}
+	byStatus := Invert(codes)
+	fmt.Println("Invert, 404 ->", byStatus[404])
+
+	// Works on strings too, once split into words
+	words := strings.Fields("the quick brown fox jumps over the lazy dog")
+	byLength := GroupBy(Uniq(words), func(w string) int { return len(w) })
+	for _, n := range SortedKeys(byLength) {
+		fmt.Printf("%d letters: %v\n", n, byLength[n])
+	}
+}
+
+func lazySequences() {
+	fmt.Println("\n=== 3. LAZY iter.Seq PIPELINES (rangeWithBreakContinue) ===")
+
+	numbers := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+
+	// Same as rangeWithBreakContinue: skip even numbers, stop after 8
+	calls := 0
+	odd := FilterSeq(slices.Values(numbers), func(n int) bool {
+		calls++
+		return n%2 == 1
+	})
+	for n := range odd {
+		if n > 8 {
+			break
+		}
+		fmt.Printf("  %d\n", n)
+	}
+	fmt.Printf("Filter ran %d times, not %d: breaking stops the pipeline\n", calls, len(numbers))
+
+	names := MapSeq(slices.Values(employees), func(e Employee) string { return e.Name })
+	for batch := range ChunkSeq(names, 2) {
+		fmt.Println("Batch:", batch)
+	}
+
+	index := maps.Collect(KeyBySeq(slices.Values(employees), func(e Employee) string { return e.Name }))
+	fmt.Println("maps.Collect(KeyBySeq):", len(index), "entries")
+
+	for name, age := range ZipSeq(slices.Values([]string{"x", "y", "z"}), slices.Values([]int{10, 20})) {
+		fmt.Printf("ZipSeq: %s=%d\n", name, age)
+	}
+
+	total := ReduceSeq(FlattenSeq(slices.Values([][]int{{1, 2}, {3}, {4, 5}})), 0,
+		func(acc, n int) int { return acc + n })
+	fmt.Println("ReduceSeq(FlattenSeq):", total)
+
+	for k := range SortedKeysSeq(maps.Collect(InvertSeq(maps.All(map[string]int{"b": 2, "a": 1})))) {
+		fmt.Println("SortedKeysSeq(InvertSeq):", k)
+	}
+}
+
+func main() {
+	fmt.Println("🧰 GO GENERIC COLLECTION HELPERS - COMPLETE GUIDE")
+	fmt.Println("=================================================")
+
+	sliceHelpers()
+	time.Sleep(300 * time.Millisecond)
+
+	mapHelpers()
+	time.Sleep(300 * time.Millisecond)
+
+	lazySequences()
+
+	// The benchmarks against hand-written loops live in main_test.go:
+	//   go test -bench . -benchmem ./collections/functional
+
+	fmt.Println("\n✅ All examples completed!")
+}
@@ -0,0 +1,243 @@
+package main
+
+import (
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"sugar/channels/leakcheck"
+)
+
+func TestChunkAppendStaysInsideChunk(t *testing.T) {
+	backing := []int{1, 2, 3, 4, 5, 99}
+	s := backing[:5] // backing[5] belongs to the caller
+
+	chunks := Chunk(s, 2)
+	for i, c := range chunks {
+		if cap(c) != len(c) {
+			t.Errorf("chunk %d: cap %d, want len %d", i, cap(c), len(c))
+		}
+	}
+
+	_ = append(chunks[0], -1)
+	_ = append(chunks[len(chunks)-1], -1)
+	if want := []int{1, 2, 3, 4, 5, 99}; !slices.Equal(backing, want) {
+		t.Fatalf("appending to chunks changed the input: %v, want %v", backing, want)
+	}
+}
+
+func TestMapAndFilter(t *testing.T) {
+	nums := []int{1, 2, 3, 4, 5}
+	double := func(n int) string { return strconv.Itoa(n * 2) }
+	odd := func(n int) bool { return n%2 == 1 }
+
+	if got, want := Map(nums, double), []string{"2", "4", "6", "8", "10"}; !slices.Equal(got, want) {
+		t.Errorf("Map = %v, want %v", got, want)
+	}
+	if got, want := slices.Collect(MapSeq(slices.Values(nums), double)), Map(nums, double); !slices.Equal(got, want) {
+		t.Errorf("MapSeq = %v, want %v", got, want)
+	}
+	if got, want := Filter(nums, odd), []int{1, 3, 5}; !slices.Equal(got, want) {
+		t.Errorf("Filter = %v, want %v", got, want)
+	}
+	if got, want := slices.Collect(FilterSeq(slices.Values(nums), odd)), Filter(nums, odd); !slices.Equal(got, want) {
+		t.Errorf("FilterSeq = %v, want %v", got, want)
+	}
+	if got := Map([]int(nil), double); len(got) != 0 {
+		t.Errorf("Map(nil) = %v, want empty", got)
+	}
+}
+
+func TestGroupBy(t *testing.T) {
+	words := []string{"apple", "avocado", "banana", "blueberry", "cherry", "apricot"}
+	first := func(w string) byte { return w[0] }
+	want := map[byte][]string{
+		'a': {"apple", "avocado", "apricot"}, // Input order kept within a group
+		'b': {"banana", "blueberry"},
+		'c': {"cherry"},
+	}
+
+	eq := func(a, b []string) bool { return slices.Equal(a, b) }
+	if got := GroupBy(words, first); !maps.EqualFunc(got, want, eq) {
+		t.Errorf("GroupBy = %v, want %v", got, want)
+	}
+	if got := GroupBySeq(slices.Values(words), first); !maps.EqualFunc(got, want, eq) {
+		t.Errorf("GroupBySeq = %v, want %v", got, want)
+	}
+}
+
+func TestZip(t *testing.T) {
+	names := []string{"a", "b", "c"}
+	ages := []int{1, 2} // Shorter: Zip stops here
+
+	want := []Pair[string, int]{{"a", 1}, {"b", 2}}
+	if got := Zip(names, ages); !slices.Equal(got, want) {
+		t.Errorf("Zip = %v, want %v", got, want)
+	}
+
+	var got []Pair[string, int]
+	for n, a := range ZipSeq(slices.Values(names), slices.Values(ages)) {
+		got = append(got, Pair[string, int]{n, a})
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("ZipSeq = %v, want %v", got, want)
+	}
+
+	// Breaking early must stop the pulled side too
+	for range ZipSeq(slices.Values(names), slices.Values(names)) {
+		break
+	}
+}
+
+func TestUniq(t *testing.T) {
+	in := []string{"b", "a", "b", "c", "a", "b"}
+	want := []string{"b", "a", "c"} // First occurrence order
+
+	if got := Uniq(in); !slices.Equal(got, want) {
+		t.Errorf("Uniq = %v, want %v", got, want)
+	}
+	if got := slices.Collect(UniqSeq(slices.Values(in))); !slices.Equal(got, want) {
+		t.Errorf("UniqSeq = %v, want %v", got, want)
+	}
+}
+
+func TestKeyBy(t *testing.T) {
+	type user struct {
+		id   int
+		name string
+	}
+	users := []user{{1, "ann"}, {2, "bob"}, {1, "ann-renamed"}}
+	id := func(u user) int { return u.id }
+	want := map[int]user{1: {1, "ann-renamed"}, 2: {2, "bob"}} // Last one wins
+
+	if got := KeyBy(users, id); !maps.Equal(got, want) {
+		t.Errorf("KeyBy = %v, want %v", got, want)
+	}
+	if got := maps.Collect(KeyBySeq(slices.Values(users), id)); !maps.Equal(got, want) {
+		t.Errorf("maps.Collect(KeyBySeq) = %v, want %v", got, want)
+	}
+}
+
+func TestInvert(t *testing.T) {
+	codes := map[string]int{"ok": 200, "not found": 404}
+	want := map[int]string{200: "ok", 404: "not found"}
+
+	if got := Invert(codes); !maps.Equal(got, want) {
+		t.Errorf("Invert = %v, want %v", got, want)
+	}
+	if got := maps.Collect(InvertSeq(maps.All(codes))); !maps.Equal(got, want) {
+		t.Errorf("InvertSeq = %v, want %v", got, want)
+	}
+
+	// Duplicate values: one of the keys survives
+	got := Invert(map[string]int{"a": 1, "b": 1})
+	if len(got) != 1 || (got[1] != "a" && got[1] != "b") {
+		t.Errorf("Invert with a shared value = %v", got)
+	}
+}
+
+func TestSeqIsLazyAndStopsEarly(t *testing.T) {
+	var calls []string
+	trace := func(name string) func(int) bool {
+		return func(n int) bool {
+			calls = append(calls, name+strconv.Itoa(n))
+			return true
+		}
+	}
+	seq := FilterSeq(MapSeq(slices.Values([]int{1, 2, 3, 4}), func(n int) int { return n * 10 }), trace("f"))
+	if len(calls) != 0 {
+		t.Fatalf("building the pipeline ran it: %v", calls)
+	}
+
+	for v := range seq {
+		if v == 20 {
+			break
+		}
+	}
+	if got := strings.Join(calls, ","); got != "f10,f20" {
+		t.Fatalf("calls = %s, want f10,f20 and nothing after the break", got)
+	}
+}
+
+// --- Eager helpers and Seq pipelines against hand-written loops ---
+
+var sink int
+
+func benchNumbers() []int {
+	numbers := make([]int, 10_000)
+	for i := range numbers {
+		numbers[i] = i
+	}
+	return numbers
+}
+
+// Filter even numbers, square them, sum
+
+func BenchmarkManualLoop(b *testing.B) {
+	numbers := benchNumbers()
+	b.ReportAllocs()
+	for b.Loop() {
+		sum := 0
+		for _, n := range numbers {
+			if n%2 == 0 {
+				sum += n * n
+			}
+		}
+		sink = sum
+	}
+}
+
+func BenchmarkFilterMapReduce(b *testing.B) {
+	numbers := benchNumbers()
+	b.ReportAllocs()
+	for b.Loop() {
+		even := Filter(numbers, func(n int) bool { return n%2 == 0 })
+		sq := Map(even, func(n int) int { return n * n })
+		sink = Reduce(sq, 0, func(a, n int) int { return a + n })
+	}
+}
+
+// Seq pipelines skip the intermediate slices that the eager helpers allocate.
+func BenchmarkFilterMapReduceSeq(b *testing.B) {
+	numbers := benchNumbers()
+	b.ReportAllocs()
+	for b.Loop() {
+		even := FilterSeq(slices.Values(numbers), func(n int) bool { return n%2 == 0 })
+		sq := MapSeq(even, func(n int) int { return n * n })
+		sink = ReduceSeq(sq, 0, func(a, n int) int { return a + n })
+	}
+}
+
+// Group by remainder
+
+func BenchmarkManualGroupLoop(b *testing.B) {
+	numbers := benchNumbers()
+	b.ReportAllocs()
+	for b.Loop() {
+		groups := make(map[int][]int)
+		for _, n := range numbers {
+			groups[n%10] = append(groups[n%10], n)
+		}
+		sink = len(groups)
+	}
+}
+
+func BenchmarkGroupBy(b *testing.B) {
+	numbers := benchNumbers()
+	b.ReportAllocs()
+	for b.Loop() {
+		sink = len(GroupBy(numbers, func(n int) int { return n % 10 }))
+	}
+}
+
+// Examples run one at a time: leakcheck compares goroutine snapshots, so a
+// parallel test would look like a leak.
+func TestExamplesDoNotLeakOrDeadlock(t *testing.T) {
+	leakcheck.Check(t,
+		leakcheck.Example{Name: "sliceHelpers", Fn: sliceHelpers},
+		leakcheck.Example{Name: "mapHelpers", Fn: mapHelpers},
+		leakcheck.Example{Name: "lazySequences", Fn: lazySequences},
+	)
+}
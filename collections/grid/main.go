@@ -0,0 +1,417 @@
+package main
+
+import (
+	"fmt"
+	"iter"
+	"strings"
+	"time"
+)
+
+// --- 1. The Grid Type ---
+
+// Point is a cell position: X is the column, Y the row.
+type Point struct{ X, Y int }
+
+func (p Point) Add(q Point) Point { return Point{p.X + q.X, p.Y + q.Y} }
+
+// Grid is a width x height matrix stored in one contiguous slice, row by
+// row. Unlike [][]T, it takes a single allocation and walking it row by row
+// stays in cache.
+//
+// A Grid may be a view into a larger one (see Sub): stride is the distance
+// between rows in data, and offset is where cell (0, 0) lives.
+type Grid[T any] struct {
+	data          []T
+	width, height int
+	stride        int
+	offset        int
+}
+
+// NewGrid creates a grid filled with zero values.
+func NewGrid[T any](width, height int) *Grid[T] {
+	if width < 0 || height < 0 {
+		panic("grid: negative size")
+	}
+	return &Grid[T]{data: make([]T, width*height), width: width, height: height, stride: width}
+}
+
+// FromRows copies a jagged [][]T into a grid. All rows must have the same length.
+func FromRows[T any](rows [][]T) *Grid[T] {
+	if len(rows) == 0 {
+		return NewGrid[T](0, 0)
+	}
+	g := NewGrid[T](len(rows[0]), len(rows))
+	for y, row := range rows {
+		if len(row) != g.width {
+			panic(fmt.Sprintf("grid: row %d has %d cells, want %d", y, len(row), g.width))
+		}
+		copy(g.RowSlice(y), row)
+	}
+	return g
+}
+
+func (g *Grid[T]) Width() int  { return g.width }
+func (g *Grid[T]) Height() int { return g.height }
+
+// In reports whether p is inside the grid.
+func (g *Grid[T]) In(p Point) bool {
+	return p.X >= 0 && p.X < g.width && p.Y >= 0 && p.Y < g.height
+}
+
+func (g *Grid[T]) index(x, y int) int {
+	if x < 0 || x >= g.width || y < 0 || y >= g.height {
+		panic(fmt.Sprintf("grid: (%d, %d) out of range [%dx%d]", x, y, g.width, g.height))
+	}
+	return g.offset + y*g.stride + x
+}
+
+func (g *Grid[T]) At(x, y int) T     { return g.data[g.index(x, y)] }
+func (g *Grid[T]) Set(x, y int, v T) { g.data[g.index(x, y)] = v }
+
+// RowSlice returns row y as a slice that shares memory with the grid.
+// Only y is checked, so a zero-width grid has empty rows rather than none.
+func (g *Grid[T]) RowSlice(y int) []T {
+	if y < 0 || y >= g.height {
+		panic(fmt.Sprintf("grid: row %d out of range [%dx%d]", y, g.width, g.height))
+	}
+	start := g.offset + y*g.stride
+	return g.data[start : start+g.width : start+g.width]
+}
+
+// --- 2. Iterators ---
+
+// All yields every cell in row order.
+func (g *Grid[T]) All() iter.Seq2[Point, T] {
+	return func(yield func(Point, T) bool) {
+		for y := range g.height {
+			for x, v := range g.RowSlice(y) {
+				if !yield(Point{x, y}, v) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Row yields the cells of row y with their column.
+func (g *Grid[T]) Row(y int) iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		for x, v := range g.RowSlice(y) {
+			if !yield(x, v) {
+				return
+			}
+		}
+	}
+}
+
+// Col yields the cells of column x with their row.
+func (g *Grid[T]) Col(x int) iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		for y := range g.height {
+			if !yield(y, g.At(x, y)) {
+				return
+			}
+		}
+	}
+}
+
+var (
+	// Dirs4 are the orthogonal neighbours: up, right, down, left.
+	Dirs4 = []Point{{0, -1}, {1, 0}, {0, 1}, {-1, 0}}
+	// Dirs8 adds the diagonals.
+	Dirs8 = []Point{{0, -1}, {1, -1}, {1, 0}, {1, 1}, {0, 1}, {-1, 1}, {-1, 0}, {-1, -1}}
+)
+
+// Neighbors4 yields the in-bounds orthogonal neighbours of p.
+func (g *Grid[T]) Neighbors4(p Point) iter.Seq[Point] { return g.neighbors(p, Dirs4) }
+
+// Neighbors8 yields the in-bounds neighbours of p, diagonals included.
+func (g *Grid[T]) Neighbors8(p Point) iter.Seq[Point] { return g.neighbors(p, Dirs8) }
+
+func (g *Grid[T]) neighbors(p Point, dirs []Point) iter.Seq[Point] {
+	return func(yield func(Point) bool) {
+		for _, d := range dirs {
+			if n := p.Add(d); g.In(n) && !yield(n) {
+				return
+			}
+		}
+	}
+}
+
+// --- 3. Transforms and Views ---
+
+// Transpose returns a new grid with rows and columns swapped.
+func (g *Grid[T]) Transpose() *Grid[T] {
+	out := NewGrid[T](g.height, g.width)
+	for p, v := range g.All() {
+		out.Set(p.Y, p.X, v)
+	}
+	return out
+}
+
+// RotateCW returns a new grid turned 90 degrees clockwise.
+func (g *Grid[T]) RotateCW() *Grid[T] {
+	out := NewGrid[T](g.height, g.width)
+	for p, v := range g.All() {
+		out.Set(g.height-1-p.Y, p.X, v)
+	}
+	return out
+}
+
+// RotateCCW returns a new grid turned 90 degrees counter-clockwise.
+func (g *Grid[T]) RotateCCW() *Grid[T] {
+	out := NewGrid[T](g.height, g.width)
+	for p, v := range g.All() {
+		out.Set(p.Y, g.width-1-p.X, v)
+	}
+	return out
+}
+
+// Sub returns a w x h view whose (0, 0) is (x, y) in g. Nothing is copied:
+// writes through the view change g.
+func (g *Grid[T]) Sub(x, y, w, h int) *Grid[T] {
+	if x < 0 || y < 0 || w < 0 || h < 0 || x+w > g.width || y+h > g.height {
+		panic(fmt.Sprintf("grid: sub-view (%d, %d) %dx%d out of range [%dx%d]", x, y, w, h, g.width, g.height))
+	}
+	return &Grid[T]{data: g.data, width: w, height: h, stride: g.stride, offset: g.offset + y*g.stride + x}
+}
+
+// Clone returns a compact copy, detached from any grid it was a view of.
+func (g *Grid[T]) Clone() *Grid[T] {
+	out := NewGrid[T](g.width, g.height)
+	for y := range g.height {
+		copy(out.RowSlice(y), g.RowSlice(y))
+	}
+	return out
+}
+
+// --- 4. Flood Fill and BFS ---
+
+// FloodFill replaces the 4-connected region of cells equal to g[start]
+// with value, and returns how many cells changed.
+func FloodFill[T comparable](g *Grid[T], start Point, value T) int {
+	target := g.At(start.X, start.Y)
+	if target == value {
+		return 0
+	}
+
+	filled := 0
+	queue := []Point{start}
+	g.Set(start.X, start.Y, value)
+	for len(queue) > 0 {
+		p := queue[0]
+		queue = queue[1:]
+		filled++
+		for n := range g.Neighbors4(p) {
+			if g.At(n.X, n.Y) == target {
+				g.Set(n.X, n.Y, value) // Mark on enqueue so no cell is queued twice
+				queue = append(queue, n)
+			}
+		}
+	}
+	return filled
+}
+
+// ShortestPath finds a shortest 4-connected path from start to goal
+// through cells where passable is true. The path includes both ends, so
+// there is none if either end is outside the grid or not passable.
+func ShortestPath[T any](g *Grid[T], start, goal Point, passable func(T) bool) ([]Point, bool) {
+	for _, end := range []Point{start, goal} {
+		if !g.In(end) || !passable(g.At(end.X, end.Y)) {
+			return nil, false
+		}
+	}
+
+	const unvisited = -1
+	prev := NewGrid[int](g.width, g.height) // Index of the cell we came from
+	for i := range prev.data {
+		prev.data[i] = unvisited
+	}
+	flat := func(p Point) int { return p.Y*g.width + p.X }
+
+	prev.Set(start.X, start.Y, flat(start))
+	queue := []Point{start}
+	for len(queue) > 0 {
+		p := queue[0]
+		queue = queue[1:]
+		if p == goal {
+			var path []Point
+			for {
+				path = append(path, p)
+				if p == start {
+					break
+				}
+				i := prev.At(p.X, p.Y)
+				p = Point{i % g.width, i / g.width}
+			}
+			for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+				path[i], path[j] = path[j], path[i]
+			}
+			return path, true
+		}
+		for n := range g.Neighbors4(p) {
+			if prev.At(n.X, n.Y) == unvisited && passable(g.At(n.X, n.Y)) {
+				prev.Set(n.X, n.Y, flat(p))
+				queue = append(queue, n)
+			}
+		}
+	}
+	return nil, false
+}
+
+// --- 5. Examples ---
+
+// printGrid shows a rune grid, one row per line.
+func printGrid(g *Grid[rune]) {
+	for y := range g.Height() {
+		fmt.Println("  " + string(g.RowSlice(y)))
+	}
+}
+
+func gridFromString(s string) *Grid[rune] {
+	var rows [][]rune
+	for _, line := range strings.Split(strings.TrimSpace(s), "\n") {
+		rows = append(rows, []rune(strings.TrimSpace(line)))
+	}
+	return FromRows(rows)
+}
+
+func rangeOverNestedAsGrid() {
+	fmt.Println("\n=== 1. rangeOverNested AS A GRID ===")
+
+	matrix := FromRows([][]int{
+		{1, 2, 3},
+		{4, 5, 6},
+		{7, 8, 9},
+	})
+
+	for y := range matrix.Height() {
+		fmt.Print("  ")
+		for _, v := range matrix.Row(y) {
+			fmt.Printf("%d ", v)
+		}
+		fmt.Println()
+	}
+
+	colSum := 0
+	for _, v := range matrix.Col(1) {
+		colSum += v
+	}
+	fmt.Println("Sum of column 1:", colSum)
+
+	center := Point{1, 1}
+	var n4, n8 []int
+	for p := range matrix.Neighbors4(center) {
+		n4 = append(n4, matrix.At(p.X, p.Y))
+	}
+	for p := range matrix.Neighbors8(Point{0, 0}) {
+		n8 = append(n8, matrix.At(p.X, p.Y))
+	}
+	fmt.Println("4-neighbours of center:", n4)
+	fmt.Println("8-neighbours of corner:", n8)
+}
+
+func transforms() {
+	fmt.Println("\n=== 2. TRANSPOSE AND ROTATE ===")
+
+	g := gridFromString(`
+		ABCD
+		EFGH`)
+
+	fmt.Println("Original:")
+	printGrid(g)
+	fmt.Println("Transpose:")
+	printGrid(g.Transpose())
+	fmt.Println("RotateCW:")
+	printGrid(g.RotateCW())
+	fmt.Println("RotateCCW:")
+	printGrid(g.RotateCCW())
+}
+
+func subViews() {
+	fmt.Println("\n=== 3. SUB-VIEWS SHARE MEMORY ===")
+
+	img := gridFromString(`
+		........
+		........
+		........
+		........`)
+
+	tile := img.Sub(2, 1, 4, 2)
+	for p := range tile.All() {
+		tile.Set(p.X, p.Y, '#')
+	}
+	tile.Set(0, 0, '@') // (0, 0) of the tile is (2, 1) of the image
+
+	fmt.Printf("Wrote into a %dx%d view; the image changed:\n", tile.Width(), tile.Height())
+	printGrid(img)
+
+	copyOf := tile.Clone()
+	copyOf.Set(1, 1, 'X')
+	fmt.Println("Clone is detached; image unchanged:", img.At(3, 2) == '#')
+}
+
+func floodFillExample() {
+	fmt.Println("\n=== 4. FLOOD FILL ===")
+
+	img := gridFromString(`
+		..##....
+		.#..#...
+		.#...#..
+		..###...`)
+
+	n := FloodFill(img, Point{3, 1}, 'o')
+	fmt.Printf("Filled %d cells inside the shape:\n", n)
+	printGrid(img)
+}
+
+func pathFinding() {
+	fmt.Println("\n=== 5. BFS SHORTEST PATH ===")
+
+	maze := gridFromString(`
+		S.#.....
+		.##.###.
+		....#...
+		.##...#G`)
+
+	var start, goal Point
+	for p, c := range maze.All() {
+		switch c {
+		case 'S':
+			start = p
+		case 'G':
+			goal = p
+		}
+	}
+
+	path, ok := ShortestPath(maze, start, goal, func(c rune) bool { return c != '#' })
+	fmt.Printf("Found: %t, %d steps\n", ok, len(path)-1)
+	for _, p := range path[1 : len(path)-1] {
+		maze.Set(p.X, p.Y, '*')
+	}
+	printGrid(maze)
+}
+
+func main() {
+	fmt.Println("🔲 GO GENERIC 2D GRID - COMPLETE GUIDE")
+	fmt.Println("======================================")
+
+	rangeOverNestedAsGrid()
+	time.Sleep(300 * time.Millisecond)
+
+	transforms()
+	time.Sleep(300 * time.Millisecond)
+
+	subViews()
+	time.Sleep(300 * time.Millisecond)
+
+	floodFillExample()
+	time.Sleep(300 * time.Millisecond)
+
+	pathFinding()
+
+	// The comparison with [][]T lives in main_test.go:
+	//   go test -bench . -benchmem ./collections/grid
+
+	fmt.Println("\n✅ All examples completed!")
+}
@@ -0,0 +1,307 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"sugar/channels/leakcheck"
+)
+
+func TestZeroWidthGrid(t *testing.T) {
+	g := FromRows([][]int{{}, {}})
+	if g.Width() != 0 || g.Height() != 2 {
+		t.Fatalf("FromRows({{}, {}}) is %dx%d, want 0x2", g.Width(), g.Height())
+	}
+	if row := g.RowSlice(1); len(row) != 0 {
+		t.Errorf("RowSlice(1) = %v, want empty", row)
+	}
+	for p := range g.All() {
+		t.Errorf("All yielded %v for a zero-width grid", p)
+	}
+	if tr := g.Transpose(); tr.Width() != 2 || tr.Height() != 0 {
+		t.Errorf("Transpose() is %dx%d, want 2x0", tr.Width(), tr.Height())
+	}
+	if c := g.Clone(); c.Height() != 2 {
+		t.Errorf("Clone() height = %d, want 2", c.Height())
+	}
+	if _, ok := ShortestPath(g, Point{0, 0}, Point{0, 1}, func(int) bool { return true }); ok {
+		t.Error("ShortestPath found a path in a zero-width grid")
+	}
+}
+
+func TestShortestPathRejectsBadEnds(t *testing.T) {
+	maze := gridFromString(`
+		..#
+		...`)
+	open := func(c rune) bool { return c != '#' }
+
+	tests := []struct {
+		name        string
+		start, goal Point
+	}{
+		{"start outside", Point{-1, 0}, Point{2, 1}},
+		{"goal outside", Point{0, 0}, Point{3, 1}},
+		{"start is a wall", Point{2, 0}, Point{0, 0}},
+		{"goal is a wall", Point{0, 0}, Point{2, 0}},
+		{"start is the goal and a wall", Point{2, 0}, Point{2, 0}},
+	}
+	for _, tt := range tests {
+		if path, ok := ShortestPath(maze, tt.start, tt.goal, open); ok || path != nil {
+			t.Errorf("%s: ShortestPath = %v, %t, want nil, false", tt.name, path, ok)
+		}
+	}
+
+	path, ok := ShortestPath(maze, Point{0, 0}, Point{2, 1}, open)
+	if !ok || len(path) != 4 {
+		t.Errorf("ShortestPath around the wall = %v, %t, want 4 cells", path, ok)
+	}
+}
+
+// gridString is the inverse of gridFromString, with "/" between rows.
+func gridString(g *Grid[rune]) string {
+	rows := make([]string, g.Height())
+	for y := range rows {
+		rows[y] = string(g.RowSlice(y))
+	}
+	return strings.Join(rows, "/")
+}
+
+func TestTransforms(t *testing.T) {
+	g := gridFromString(`
+		abc
+		def`)
+
+	tests := []struct {
+		name string
+		got  *Grid[rune]
+		want string
+	}{
+		{"Transpose", g.Transpose(), "ad/be/cf"},
+		{"RotateCW", g.RotateCW(), "da/eb/fc"},
+		{"RotateCCW", g.RotateCCW(), "cf/be/ad"},
+		{"RotateCW twice", g.RotateCW().RotateCW(), "fed/cba"},
+		{"RotateCW then RotateCCW", g.RotateCW().RotateCCW(), "abc/def"},
+		{"Transpose twice", g.Transpose().Transpose(), "abc/def"},
+		{"RotateCW of a view", g.Sub(1, 0, 2, 2).RotateCW(), "eb/fc"},
+	}
+	for _, tt := range tests {
+		if got := gridString(tt.got); got != tt.want {
+			t.Errorf("%s = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+	if gridString(g) != "abc/def" {
+		t.Errorf("transforms changed the original: %s", gridString(g))
+	}
+}
+
+func TestSubWritesThrough(t *testing.T) {
+	g := gridFromString(`
+		.....
+		.....
+		.....
+		.....`)
+
+	sub := g.Sub(1, 1, 3, 2) // Stride 5, offset 6
+	sub.Set(0, 0, 'a')
+	sub.Set(2, 1, 'b')
+	sub.RowSlice(1)[0] = 'c'
+	nested := sub.Sub(1, 1, 2, 1) // A view of a view keeps the stride of g
+	nested.Set(0, 0, 'd')
+
+	if got, want := gridString(g), "...../.a.../.cdb./....."; got != want {
+		t.Fatalf("grid after writes through views = %s, want %s", got, want)
+	}
+	if got, want := gridString(sub), "a../cdb"; got != want {
+		t.Fatalf("sub = %s, want %s", got, want)
+	}
+	if row := sub.RowSlice(0); len(row) != 3 {
+		t.Fatalf("sub.RowSlice(0) has %d cells, want 3", len(row))
+	}
+
+	var seen []Point
+	for p := range sub.All() {
+		seen = append(seen, p)
+	}
+	if len(seen) != 6 || seen[5] != (Point{2, 1}) {
+		t.Fatalf("sub.All() yielded %v, want the 6 cells of a 3x2 view", seen)
+	}
+
+	clone := sub.Clone()
+	clone.Set(1, 0, 'z')
+	if g.At(2, 1) == 'z' {
+		t.Fatal("writing to a Clone changed the grid it was cut from")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("Sub past the right edge did not panic")
+		}
+	}()
+	sub.Sub(2, 0, 2, 1)
+}
+
+func TestFloodFill(t *testing.T) {
+	tests := []struct {
+		name  string
+		start Point
+		value rune
+		want  int
+		after string
+	}{
+		{"top-left pocket", Point{0, 0}, 'x', 3, "xx#../x##../#...."},
+		{"right side wraps under the wall", Point{4, 0}, 'o', 8, "..#oo/.##oo/#oooo"},
+		{"wall cells", Point{1, 1}, '=', 3, "..=../.==../#...."},
+		{"single cell", Point{0, 2}, '=', 1, "..#../.##../=...."},
+		{"same value", Point{0, 0}, '.', 0, "..#../.##../#...."},
+	}
+	for _, tt := range tests {
+		g := gridFromString(`
+			..#..
+			.##..
+			#....`)
+		if got := FloodFill(g, tt.start, tt.value); got != tt.want {
+			t.Errorf("%s: FloodFill = %d, want %d", tt.name, got, tt.want)
+		}
+		if got := gridString(g); got != tt.after {
+			t.Errorf("%s: grid = %s, want %s", tt.name, got, tt.after)
+		}
+	}
+}
+
+func TestShortestPath(t *testing.T) {
+	open := func(c rune) bool { return c != '#' }
+	maze := gridFromString(`
+		....#
+		.##.#
+		.#...
+		...#.`)
+
+	// checkPath verifies that path is a walk of n cells through open cells
+	checkPath := func(name string, path []Point, start, goal Point, n int) {
+		t.Helper()
+		if len(path) != n || path[0] != start || path[len(path)-1] != goal {
+			t.Errorf("%s: path = %v, want %d cells from %v to %v", name, path, n, start, goal)
+			return
+		}
+		for i, p := range path {
+			if !open(maze.At(p.X, p.Y)) {
+				t.Errorf("%s: path goes through the wall at %v", name, p)
+			}
+			if i > 0 {
+				if d := abs(p.X-path[i-1].X) + abs(p.Y-path[i-1].Y); d != 1 {
+					t.Errorf("%s: %v to %v is not one step", name, path[i-1], p)
+				}
+			}
+		}
+	}
+
+	// Over the top is 8 cells, round the left side 10
+	path, ok := ShortestPath(maze, Point{0, 0}, Point{4, 3}, open)
+	if !ok {
+		t.Fatal("ShortestPath found no path")
+	}
+	checkPath("across", path, Point{0, 0}, Point{4, 3}, 8)
+
+	path, ok = ShortestPath(maze, Point{2, 3}, Point{2, 3}, open)
+	if !ok {
+		t.Fatal("ShortestPath from a cell to itself found no path")
+	}
+	checkPath("to itself", path, Point{2, 3}, Point{2, 3}, 1)
+
+	walled := gridFromString(`
+		..#..
+		..#..`)
+	if path, ok := ShortestPath(walled, Point{0, 0}, Point{4, 1}, open); ok || path != nil {
+		t.Errorf("ShortestPath through a wall = %v, %t, want nil, false", path, ok)
+	}
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// --- Grid[T] vs [][]T ---
+
+const benchW, benchH = 512, 512
+
+var sink int
+
+func BenchmarkAllocJagged(b *testing.B) {
+	b.ReportAllocs()
+	for b.Loop() {
+		m := make([][]int, benchH)
+		for y := range m {
+			m[y] = make([]int, benchW)
+		}
+		sink += len(m)
+	}
+}
+
+// One allocation instead of one per row.
+func BenchmarkAllocGrid(b *testing.B) {
+	b.ReportAllocs()
+	for b.Loop() {
+		sink += NewGrid[int](benchW, benchH).Width()
+	}
+}
+
+func BenchmarkSumJagged(b *testing.B) {
+	jagged := make([][]int, benchH)
+	for y := range jagged {
+		jagged[y] = make([]int, benchW)
+	}
+	for b.Loop() {
+		for y := range jagged {
+			for x := range jagged[y] {
+				sink += jagged[y][x]
+			}
+		}
+	}
+}
+
+func BenchmarkSumGridAt(b *testing.B) {
+	grid := NewGrid[int](benchW, benchH)
+	for b.Loop() {
+		for y := range benchH {
+			for x := range benchW {
+				sink += grid.At(x, y)
+			}
+		}
+	}
+}
+
+// For hot loops, RowSlice avoids At's per-cell bounds checks.
+func BenchmarkSumGridRowSlice(b *testing.B) {
+	grid := NewGrid[int](benchW, benchH)
+	for b.Loop() {
+		for y := range benchH {
+			for _, v := range grid.RowSlice(y) {
+				sink += v
+			}
+		}
+	}
+}
+
+func BenchmarkSumGridAll(b *testing.B) {
+	grid := NewGrid[int](benchW, benchH)
+	for b.Loop() {
+		for _, v := range grid.All() {
+			sink += v
+		}
+	}
+}
+
+// Examples run one at a time: leakcheck compares goroutine snapshots, so a
+// parallel test would look like a leak.
+func TestExamplesDoNotLeakOrDeadlock(t *testing.T) {
+	leakcheck.Check(t,
+		leakcheck.Example{Name: "rangeOverNestedAsGrid", Fn: rangeOverNestedAsGrid},
+		leakcheck.Example{Name: "transforms", Fn: transforms},
+		leakcheck.Example{Name: "subViews", Fn: subViews},
+		leakcheck.Example{Name: "floodFillExample", Fn: floodFillExample},
+		leakcheck.Example{Name: "pathFinding", Fn: pathFinding},
+	)
+}
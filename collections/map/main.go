@@ -1,6 +1,10 @@
 package main
 
-import "fmt"
+import (
+	"fmt"
+
+	"sugar/panics"
+)
 
 func LetterFrequencies(word string) {
 	m := make(map[rune]int, len(word))
@@ -19,7 +23,7 @@ func WhatHappend() {
 
 	defer func() {
 		if r := recover(); r != nil {
-			fmt.Println("Recovered from panic:", r)
+			fmt.Println("Recovered from panic:", panics.Recovered(r).Value)
 		}
 	}()
 
@@ -29,6 +33,7 @@ func WhatHappend() {
 	val, ok := m["key"]
 	fmt.Printf("I check and read from nil map: %d (ok=%v)\n", val, ok)
 
+	//sugarlint:ignore nilmap shows the nil-map write panic
 	m["key"] = 1
 }
 
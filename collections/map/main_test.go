@@ -0,0 +1,16 @@
+package main
+
+import (
+	"testing"
+
+	"sugar/channels/leakcheck"
+)
+
+// Examples run one at a time: leakcheck compares goroutine snapshots, so a
+// parallel test would look like a leak.
+func TestExamplesDoNotLeakOrDeadlock(t *testing.T) {
+	leakcheck.Check(t,
+		leakcheck.Example{Name: "LetterFrequencies", Fn: func() { LetterFrequencies("aabbc") }},
+		leakcheck.Example{Name: "WhatHappend", Fn: WhatHappend},
+	)
+}
@@ -0,0 +1,549 @@
+package main
+
+import (
+	"bytes"
+	"cmp"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"math/rand/v2"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// --- 1. OrderedMap: Insertion Order ---
+
+// entry is a node in OrderedMap's doubly linked list.
+type entry[K comparable, V any] struct {
+	key        K
+	value      V
+	prev, next *entry[K, V]
+}
+
+// OrderedMap is a map that iterates in insertion order. Lookups go through
+// a normal map; a linked list remembers the order, so Delete and
+// MoveToFront are O(1).
+type OrderedMap[K comparable, V any] struct {
+	index map[K]*entry[K, V]
+	root  entry[K, V] // Sentinel: root.next is the first entry, root.prev the last
+}
+
+// NewOrderedMap creates an empty OrderedMap.
+func NewOrderedMap[K comparable, V any]() *OrderedMap[K, V] {
+	m := &OrderedMap[K, V]{}
+	m.reset()
+	return m
+}
+
+// reset empties m in place. The sentinel must point at m's own root, so
+// an OrderedMap cannot be initialised by copying another one.
+func (m *OrderedMap[K, V]) reset() {
+	m.index = make(map[K]*entry[K, V])
+	m.root.next, m.root.prev = &m.root, &m.root
+}
+
+func (m *OrderedMap[K, V]) Len() int { return len(m.index) }
+
+func (m *OrderedMap[K, V]) Get(key K) (V, bool) {
+	if e, ok := m.index[key]; ok {
+		return e.value, true
+	}
+	var zero V
+	return zero, false
+}
+
+// Set adds key at the end, or updates its value in place if it exists.
+func (m *OrderedMap[K, V]) Set(key K, value V) {
+	if e, ok := m.index[key]; ok {
+		e.value = value
+		return
+	}
+	e := &entry[K, V]{key: key, value: value}
+	m.insertAfter(e, m.root.prev)
+	m.index[key] = e
+}
+
+// Delete removes key and reports whether it was present.
+func (m *OrderedMap[K, V]) Delete(key K) bool {
+	e, ok := m.index[key]
+	if !ok {
+		return false
+	}
+	m.unlink(e)
+	delete(m.index, key)
+	return true
+}
+
+// MoveToFront makes key the first entry, e.g. for most-recently-used order.
+// The entry is replaced by a new node rather than relinked: an All loop
+// standing on the old one keeps going forward instead of jumping back to
+// the front.
+func (m *OrderedMap[K, V]) MoveToFront(key K) bool {
+	e, ok := m.index[key]
+	if !ok {
+		return false
+	}
+	m.unlink(e)
+	moved := &entry[K, V]{key: key, value: e.value}
+	m.insertAfter(moved, &m.root)
+	m.index[key] = moved
+	return true
+}
+
+func (m *OrderedMap[K, V]) insertAfter(e, at *entry[K, V]) {
+	e.prev, e.next = at, at.next
+	at.next.prev = e
+	at.next = e
+}
+
+// unlink takes e out of the list but leaves e.next alone, so an All loop
+// holding e can still find the entries after it.
+func (m *OrderedMap[K, V]) unlink(e *entry[K, V]) {
+	e.prev.next = e.next
+	e.next.prev = e.prev
+	e.prev = nil
+}
+
+// live reports whether e is still in the map, and not a node left behind
+// by Delete or MoveToFront.
+func (m *OrderedMap[K, V]) live(e *entry[K, V]) bool {
+	return m.index[e.key] == e
+}
+
+// All yields the entries in order. The loop body may Set, Delete or
+// MoveToFront any key: removed entries are not yielded once removed, and
+// entries moved to the front are behind the loop and not yielded again.
+func (m *OrderedMap[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for e := m.root.next; e != &m.root; e = e.next {
+			if !m.live(e) {
+				continue // Removed by an earlier iteration; its next still leads on
+			}
+			if !yield(e.key, e.value) {
+				return
+			}
+		}
+	}
+}
+
+// Keys yields the keys in order.
+func (m *OrderedMap[K, V]) Keys() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for k := range m.All() {
+			if !yield(k) {
+				return
+			}
+		}
+	}
+}
+
+// MarshalJSON writes a JSON object with the keys in map order. Keys follow
+// encoding/json's rules for map keys: strings, integers or TextMarshalers.
+func (m *OrderedMap[K, V]) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	first := true
+	for k, v := range m.All() {
+		if !first {
+			buf.WriteByte(',')
+		}
+		first = false
+
+		ks, err := keyString(k)
+		if err != nil {
+			return nil, err
+		}
+		kb, _ := json.Marshal(ks)
+		buf.Write(kb)
+		buf.WriteByte(':')
+
+		vb, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(vb)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON reads a JSON object, keeping the order of its keys. It
+// replaces whatever m held before, like decoding into a fresh map.
+func (m *OrderedMap[K, V]) UnmarshalJSON(data []byte) error {
+	m.reset()
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return fmt.Errorf("ordered map: expected JSON object")
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		var key K
+		if err := parseKey(tok.(string), &key); err != nil {
+			return err
+		}
+		var value V
+		if err := dec.Decode(&value); err != nil {
+			return err
+		}
+		m.Set(key, value)
+	}
+	_, err := dec.Token() // Closing '}'
+	return err
+}
+
+func keyString(k any) (string, error) {
+	if tm, ok := k.(encoding.TextMarshaler); ok {
+		b, err := tm.MarshalText()
+		return string(b), err
+	}
+	v := reflect.ValueOf(k)
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	}
+	return "", fmt.Errorf("ordered map: unsupported key type %T", k)
+}
+
+func parseKey(s string, dst any) error {
+	if tu, ok := dst.(encoding.TextUnmarshaler); ok {
+		return tu.UnmarshalText([]byte(s))
+	}
+	v := reflect.ValueOf(dst).Elem()
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		v.SetInt(n)
+		return err
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		v.SetUint(n)
+		return err
+	}
+	return fmt.Errorf("ordered map: unsupported key type %s", v.Type())
+}
+
+// --- 2. SortedMap: Key Order, Backed by a Skip List ---
+
+const maxLevel = 24 // Enough for ~16M entries at p = 1/2
+
+// skipNode holds one key. next[i] is the following node on level i;
+// level 0 links every node, higher levels skip ahead.
+type skipNode[K cmp.Ordered, V any] struct {
+	key   K
+	value V
+	next  []*skipNode[K, V]
+}
+
+// SortedMap keeps its keys sorted, with O(log n) Set, Get and Delete and
+// ordered range queries. A skip list is much shorter to write than a
+// balanced tree and performs about as well.
+type SortedMap[K cmp.Ordered, V any] struct {
+	head  skipNode[K, V] // Sentinel with no key
+	level int            // Number of levels in use
+	len   int
+}
+
+// NewSortedMap creates an empty SortedMap.
+func NewSortedMap[K cmp.Ordered, V any]() *SortedMap[K, V] {
+	return &SortedMap[K, V]{head: skipNode[K, V]{next: make([]*skipNode[K, V], maxLevel)}, level: 1}
+}
+
+func (m *SortedMap[K, V]) Len() int { return m.len }
+
+// findPath fills path[i] with the last node on level i whose key is < key,
+// and returns the level-0 successor: the first node with key >= key.
+func (m *SortedMap[K, V]) findPath(key K, path *[maxLevel]*skipNode[K, V]) *skipNode[K, V] {
+	n := &m.head
+	for i := m.level - 1; i >= 0; i-- {
+		for n.next[i] != nil && n.next[i].key < key {
+			n = n.next[i]
+		}
+		if path != nil {
+			path[i] = n
+		}
+	}
+	return n.next[0]
+}
+
+func randomLevel() int {
+	level := 1
+	for level < maxLevel && rand.IntN(2) == 0 {
+		level++
+	}
+	return level
+}
+
+func (m *SortedMap[K, V]) Get(key K) (V, bool) {
+	if n := m.findPath(key, nil); n != nil && n.key == key {
+		return n.value, true
+	}
+	var zero V
+	return zero, false
+}
+
+// Set adds or updates key.
+func (m *SortedMap[K, V]) Set(key K, value V) {
+	var path [maxLevel]*skipNode[K, V]
+	if n := m.findPath(key, &path); n != nil && n.key == key {
+		n.value = value
+		return
+	}
+
+	level := randomLevel()
+	for i := m.level; i < level; i++ {
+		path[i] = &m.head // New levels start at the head
+	}
+	m.level = max(m.level, level)
+
+	n := &skipNode[K, V]{key: key, value: value, next: make([]*skipNode[K, V], level)}
+	for i := range level {
+		n.next[i] = path[i].next[i]
+		path[i].next[i] = n
+	}
+	m.len++
+}
+
+// Delete removes key and reports whether it was present.
+func (m *SortedMap[K, V]) Delete(key K) bool {
+	var path [maxLevel]*skipNode[K, V]
+	n := m.findPath(key, &path)
+	if n == nil || n.key != key {
+		return false
+	}
+	for i := range n.next {
+		path[i].next[i] = n.next[i]
+	}
+	for m.level > 1 && m.head.next[m.level-1] == nil {
+		m.level--
+	}
+	m.len--
+	return true
+}
+
+// Ceiling returns the smallest key >= key.
+func (m *SortedMap[K, V]) Ceiling(key K) (K, V, bool) {
+	if n := m.findPath(key, nil); n != nil {
+		return n.key, n.value, true
+	}
+	var zk K
+	var zv V
+	return zk, zv, false
+}
+
+// Floor returns the largest key <= key.
+func (m *SortedMap[K, V]) Floor(key K) (K, V, bool) {
+	var path [maxLevel]*skipNode[K, V]
+	if n := m.findPath(key, &path); n != nil && n.key == key {
+		return n.key, n.value, true
+	}
+	if p := path[0]; p != &m.head {
+		return p.key, p.value, true // Last node < key
+	}
+	var zk K
+	var zv V
+	return zk, zv, false
+}
+
+// Min returns the smallest key.
+func (m *SortedMap[K, V]) Min() (K, V, bool) {
+	if n := m.head.next[0]; n != nil {
+		return n.key, n.value, true
+	}
+	var zk K
+	var zv V
+	return zk, zv, false
+}
+
+// All yields every entry in ascending key order.
+func (m *SortedMap[K, V]) All() iter.Seq2[K, V] {
+	return m.from(func() *skipNode[K, V] { return m.head.next[0] }, func(K) bool { return true })
+}
+
+// Range yields the entries with lo <= key < hi in ascending order.
+func (m *SortedMap[K, V]) Range(lo, hi K) iter.Seq2[K, V] {
+	return m.from(func() *skipNode[K, V] { return m.findPath(lo, nil) }, func(k K) bool { return k < hi })
+}
+
+// from looks up the first node when the loop starts, not when the sequence
+// is built, so a sequence kept around sees entries set or deleted since.
+func (m *SortedMap[K, V]) from(start func() *skipNode[K, V], inRange func(K) bool) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for n := start(); n != nil && inRange(n.key); n = n.next[0] {
+			if !yield(n.key, n.value) {
+				return
+			}
+		}
+	}
+}
+
+// --- 3. Examples ---
+
+func letterFrequenciesOrdered(word string) {
+	fmt.Println("\n=== 1. LetterFrequencies IN A STABLE ORDER ===")
+
+	firstSeen := NewOrderedMap[string, int]()
+	alphabetical := NewSortedMap[rune, int]()
+	for _, r := range word {
+		n, _ := firstSeen.Get(string(r))
+		firstSeen.Set(string(r), n+1)
+
+		c, _ := alphabetical.Get(r)
+		alphabetical.Set(r, c+1)
+	}
+
+	fmt.Print("First-seen order:")
+	for k, v := range firstSeen.All() {
+		fmt.Printf(" %s=%d", k, v)
+	}
+	fmt.Print("\nAlphabetical:    ")
+	for k, v := range alphabetical.All() {
+		fmt.Printf(" %c=%d", k, v)
+	}
+	fmt.Println("\nSame output on every run - safe for golden tests")
+}
+
+func orderedMapOperations() {
+	fmt.Println("\n=== 2. ORDERED MAP: SET, DELETE, MOVE TO FRONT ===")
+
+	ages := NewOrderedMap[string, int]()
+	ages.Set("Alice", 25)
+	ages.Set("Bob", 30)
+	ages.Set("Charlie", 35)
+	ages.Set("Alice", 26) // Update keeps Alice's position
+
+	show := func(label string) {
+		fmt.Printf("%-18s", label)
+		for k, v := range ages.All() {
+			fmt.Printf(" %s:%d", k, v)
+		}
+		fmt.Println()
+	}
+	show("After Set:")
+
+	ages.MoveToFront("Charlie")
+	show("MoveToFront:")
+
+	ages.Delete("Bob")
+	show("Delete Bob:")
+
+	// Delete while ranging is safe
+	for k, v := range ages.All() {
+		if v > 30 {
+			ages.Delete(k)
+		}
+	}
+	show("Delete age > 30:")
+}
+
+func orderedJSON() {
+	fmt.Println("\n=== 3. JSON IN ORDER ===")
+
+	config := NewOrderedMap[string, any]()
+	config.Set("name", "api")
+	config.Set("version", 3)
+	config.Set("replicas", 2)
+	config.Set("enabled", true)
+
+	b, _ := json.Marshal(config)
+	fmt.Println("OrderedMap:", string(b))
+
+	plain := map[string]any{"name": "api", "version": 3, "replicas": 2, "enabled": true}
+	b2, _ := json.Marshal(plain)
+	fmt.Println("Plain map: ", string(b2), "(sorted by encoding/json, not insertion order)")
+
+	back := NewOrderedMap[string, int]()
+	err := json.Unmarshal([]byte(`{"zebra": 1, "apple": 2, "mango": 3}`), back)
+	fmt.Print("Round trip:")
+	for k := range back.Keys() {
+		fmt.Print(" ", k)
+	}
+	fmt.Println(" err:", err)
+
+	ports := NewOrderedMap[int, string]()
+	ports.Set(8080, "http")
+	ports.Set(22, "ssh")
+	b3, _ := json.Marshal(ports)
+	fmt.Println("Integer keys:", string(b3))
+}
+
+func sortedMapQueries() {
+	fmt.Println("\n=== 4. SORTED MAP: RANGE, FLOOR, CEILING ===")
+
+	// Price tiers keyed by minimum order quantity
+	tiers := NewSortedMap[int, float64]()
+	for qty, price := range map[int]float64{1: 9.99, 10: 8.99, 50: 7.49, 100: 5.99, 500: 4.99} {
+		tiers.Set(qty, price) // Random insertion order, sorted iteration
+	}
+
+	for _, qty := range []int{1, 7, 50, 250, 1000} {
+		tier, price, _ := tiers.Floor(qty)
+		fmt.Printf("Order of %4d -> tier %3d at $%.2f\n", qty, tier, price)
+	}
+
+	next, _, ok := tiers.Ceiling(51)
+	fmt.Printf("Next tier after 51: %d (%t)\n", next, ok)
+
+	fmt.Print("Tiers in [10, 100):")
+	for qty, price := range tiers.Range(10, 100) {
+		fmt.Printf(" %d=$%.2f", qty, price)
+	}
+	fmt.Println()
+
+	tiers.Delete(50)
+	low, _, _ := tiers.Min()
+	fmt.Printf("After Delete(50): %d tiers, min %d\n", tiers.Len(), low)
+}
+
+func timeSeries() {
+	fmt.Println("\n=== 5. SORTED MAP AS A TIME SERIES ===")
+
+	events := NewSortedMap[string, string]() // RFC 3339 strings sort chronologically
+	start := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	for i, msg := range []string{"deploy", "alert", "rollback", "deploy", "ok"} {
+		events.Set(start.Add(time.Duration(i)*15*time.Minute).Format(time.RFC3339), msg)
+	}
+
+	from := start.Add(20 * time.Minute).Format(time.RFC3339)
+	to := start.Add(50 * time.Minute).Format(time.RFC3339)
+	fmt.Printf("Events from %s to %s:\n", from[11:16], to[11:16])
+	for at, msg := range events.Range(from, to) {
+		fmt.Printf("  %s %s\n", at[11:16], msg)
+	}
+
+	at, msg, _ := events.Floor(start.Add(40 * time.Minute).Format(time.RFC3339))
+	fmt.Printf("Last event at or before 09:40: %s %s\n", at[11:16], msg)
+}
+
+func main() {
+	fmt.Println("🗂️ GO ORDERED AND SORTED MAPS - COMPLETE GUIDE")
+	fmt.Println("==============================================")
+
+	letterFrequenciesOrdered("mississippi")
+	time.Sleep(300 * time.Millisecond)
+
+	orderedMapOperations()
+	time.Sleep(300 * time.Millisecond)
+
+	orderedJSON()
+	time.Sleep(300 * time.Millisecond)
+
+	sortedMapQueries()
+	time.Sleep(300 * time.Millisecond)
+
+	timeSeries()
+
+	fmt.Println("\n✅ All examples completed!")
+}
@@ -0,0 +1,321 @@
+package main
+
+import (
+	"encoding/json"
+	"iter"
+	"maps"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"sugar/channels/leakcheck"
+)
+
+func abcde() *OrderedMap[string, int] {
+	m := NewOrderedMap[string, int]()
+	for i, k := range []string{"a", "b", "c", "d", "e"} {
+		m.Set(k, i)
+	}
+	return m
+}
+
+// ranged collects the keys All yields while body runs for each of them.
+func ranged(m *OrderedMap[string, int], body func(k string)) []string {
+	var got []string
+	for k := range m.All() {
+		got = append(got, k)
+		body(k)
+	}
+	return got
+}
+
+func TestAllWhileModifying(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      func(m *OrderedMap[string, int], k string)
+		want      []string
+		wantAfter []string
+	}{
+		{
+			name:      "delete current",
+			body:      func(m *OrderedMap[string, int], k string) { m.Delete(k) },
+			want:      []string{"a", "b", "c", "d", "e"},
+			wantAfter: nil,
+		},
+		{
+			name: "delete the next key",
+			body: func(m *OrderedMap[string, int], k string) {
+				if k == "b" {
+					m.Delete("c")
+				}
+			},
+			want:      []string{"a", "b", "d", "e"},
+			wantAfter: []string{"a", "b", "d", "e"},
+		},
+		{
+			name: "delete current and the next two",
+			body: func(m *OrderedMap[string, int], k string) {
+				if k == "b" {
+					m.Delete("b")
+					m.Delete("c")
+					m.Delete("d")
+				}
+			},
+			want:      []string{"a", "b", "e"},
+			wantAfter: []string{"a", "e"},
+		},
+		{
+			name: "move the next key to the front",
+			body: func(m *OrderedMap[string, int], k string) {
+				if k == "b" {
+					m.MoveToFront("c")
+				}
+			},
+			want:      []string{"a", "b", "d", "e"},
+			wantAfter: []string{"c", "a", "b", "d", "e"},
+		},
+		{
+			name:      "move current to the front",
+			body:      func(m *OrderedMap[string, int], k string) { m.MoveToFront(k) },
+			want:      []string{"a", "b", "c", "d", "e"},
+			wantAfter: []string{"e", "d", "c", "b", "a"},
+		},
+		{
+			name: "delete then set again",
+			body: func(m *OrderedMap[string, int], k string) {
+				if k == "a" {
+					m.Delete("b")
+					m.Set("b", 9) // Appended, so still ahead of the loop
+				}
+			},
+			want:      []string{"a", "c", "d", "e", "b"},
+			wantAfter: []string{"a", "c", "d", "e", "b"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := abcde()
+			got := ranged(m, func(k string) { tt.body(m, k) })
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("All yielded %v, want %v", got, tt.want)
+			}
+			if after := slices.Collect(m.Keys()); !slices.Equal(after, tt.wantAfter) {
+				t.Errorf("Keys() afterwards = %v, want %v", after, tt.wantAfter)
+			}
+			if m.Len() != len(tt.wantAfter) {
+				t.Errorf("Len() = %d, want %d", m.Len(), len(tt.wantAfter))
+			}
+		})
+	}
+}
+
+func TestMoveToFrontKeepsValue(t *testing.T) {
+	m := abcde()
+	m.MoveToFront("d")
+	m.Set("d", 42)
+	if v, _ := m.Get("d"); v != 42 {
+		t.Fatalf("Get(d) = %d after MoveToFront and Set, want 42", v)
+	}
+	if first := slices.Collect(m.Keys())[0]; first != "d" {
+		t.Fatalf("first key = %s, want d", first)
+	}
+}
+
+func TestUnmarshalJSONReplacesContents(t *testing.T) {
+	m := abcde()
+	if err := json.Unmarshal([]byte(`{"z": 1, "b": 2}`), m); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := slices.Collect(m.Keys()), []string{"z", "b"}; !slices.Equal(got, want) {
+		t.Fatalf("Keys() = %v, want %v: old entries kept or old order reused", got, want)
+	}
+	if m.Len() != 2 {
+		t.Fatalf("Len() = %d, want 2", m.Len())
+	}
+
+	// A zero OrderedMap works too, and keeps working after the decode
+	var zero OrderedMap[string, int]
+	if err := json.Unmarshal([]byte(`{"x": 1}`), &zero); err != nil {
+		t.Fatal(err)
+	}
+	zero.Set("y", 2)
+	if got, want := slices.Collect(zero.Keys()), []string{"x", "y"}; !slices.Equal(got, want) {
+		t.Fatalf("Keys() = %v, want %v", got, want)
+	}
+}
+
+// checkSkipList verifies that every level is sorted and only holds nodes
+// that are also on the level below, and that no level above m.level is used.
+func checkSkipList(t *testing.T, m *SortedMap[int, int]) {
+	t.Helper()
+	count := 0
+	for n := m.head.next[0]; n != nil; n = n.next[0] {
+		count++
+	}
+	if count != m.Len() {
+		t.Fatalf("level 0 has %d nodes, Len() = %d", count, m.Len())
+	}
+	for i := range maxLevel {
+		if i >= m.level {
+			if m.head.next[i] != nil {
+				t.Fatalf("level %d is in use but m.level = %d", i, m.level)
+			}
+			continue
+		}
+		below := map[*skipNode[int, int]]bool{}
+		if i > 0 {
+			for n := m.head.next[i-1]; n != nil; n = n.next[i-1] {
+				below[n] = true
+			}
+		}
+		for n := m.head.next[i]; n != nil; n = n.next[i] {
+			if i > 0 && !below[n] {
+				t.Fatalf("key %d is on level %d but not on level %d", n.key, i, i-1)
+			}
+			if next := n.next[i]; next != nil && next.key <= n.key {
+				t.Fatalf("level %d not sorted: %d before %d", i, n.key, next.key)
+			}
+		}
+	}
+	if m.level > 1 && m.head.next[m.level-1] == nil {
+		t.Fatalf("top level %d is empty", m.level-1)
+	}
+}
+
+func TestSortedMapSetDelete(t *testing.T) {
+	m := NewSortedMap[int, int]()
+	want := map[int]int{}
+	keys := rand.Perm(500)
+	for _, k := range keys {
+		m.Set(k, k*10)
+		want[k] = k * 10
+	}
+	m.Set(7, -1) // Update, not a second node
+	want[7] = -1
+	checkSkipList(t, m)
+
+	for i, k := range keys {
+		if i%2 == 0 {
+			if !m.Delete(k) {
+				t.Fatalf("Delete(%d) = false for a present key", k)
+			}
+			delete(want, k)
+		}
+	}
+	if m.Delete(-5) {
+		t.Fatal("Delete(-5) = true for a missing key")
+	}
+	checkSkipList(t, m)
+
+	if got := maps.Collect(m.All()); !maps.Equal(got, want) {
+		t.Fatalf("All() has %d entries, want %d", len(got), len(want))
+	}
+	var prev int
+	first := true
+	for k := range m.All() {
+		if !first && k <= prev {
+			t.Fatalf("All() yielded %d after %d", k, prev)
+		}
+		prev, first = k, false
+	}
+	for k, v := range want {
+		if got, ok := m.Get(k); !ok || got != v {
+			t.Fatalf("Get(%d) = %d, %v, want %d", k, got, ok, v)
+		}
+	}
+
+	// Emptying the map drops every level but the first
+	for k := range want {
+		m.Delete(k)
+	}
+	checkSkipList(t, m)
+	if m.Len() != 0 || m.level != 1 {
+		t.Fatalf("after deleting everything Len() = %d, level = %d, want 0 and 1", m.Len(), m.level)
+	}
+}
+
+func TestSortedMapQueries(t *testing.T) {
+	m := NewSortedMap[int, string]()
+	for _, k := range []int{10, 20, 30, 40} {
+		m.Set(k, "v")
+	}
+
+	tests := []struct {
+		key                  int
+		floor, ceiling       int
+		hasFloor, hasCeiling bool
+	}{
+		{key: 5, ceiling: 10, hasCeiling: true},
+		{key: 10, floor: 10, ceiling: 10, hasFloor: true, hasCeiling: true},
+		{key: 25, floor: 20, ceiling: 30, hasFloor: true, hasCeiling: true},
+		{key: 40, floor: 40, ceiling: 40, hasFloor: true, hasCeiling: true},
+		{key: 45, floor: 40, hasFloor: true},
+	}
+	for _, tt := range tests {
+		if k, _, ok := m.Floor(tt.key); ok != tt.hasFloor || k != tt.floor {
+			t.Errorf("Floor(%d) = %d, %v, want %d, %v", tt.key, k, ok, tt.floor, tt.hasFloor)
+		}
+		if k, _, ok := m.Ceiling(tt.key); ok != tt.hasCeiling || k != tt.ceiling {
+			t.Errorf("Ceiling(%d) = %d, %v, want %d, %v", tt.key, k, ok, tt.ceiling, tt.hasCeiling)
+		}
+	}
+
+	ranges := []struct {
+		lo, hi int
+		want   []int
+	}{
+		{lo: 10, hi: 30, want: []int{10, 20}}, // hi is exclusive
+		{lo: 15, hi: 41, want: []int{20, 30, 40}},
+		{lo: 0, hi: 10, want: nil},
+		{lo: 50, hi: 60, want: nil},
+		{lo: 30, hi: 20, want: nil},
+	}
+	for _, r := range ranges {
+		var got []int
+		for k := range m.Range(r.lo, r.hi) {
+			got = append(got, k)
+		}
+		if !slices.Equal(got, r.want) {
+			t.Errorf("Range(%d, %d) = %v, want %v", r.lo, r.hi, got, r.want)
+		}
+	}
+}
+
+func TestSortedMapSequenceSeesLaterChanges(t *testing.T) {
+	m := NewSortedMap[int, string]()
+	m.Set(20, "b")
+	all := m.All()
+	inRange := m.Range(10, 30)
+
+	// Built while 20 was first; iterated after 10 and 15 were added
+	// and 20 was deleted
+	m.Set(10, "a")
+	m.Set(15, "a2")
+	m.Delete(20)
+
+	keys := func(seq iter.Seq2[int, string]) []int {
+		var got []int
+		for k := range seq {
+			got = append(got, k)
+		}
+		return got
+	}
+	if got, want := keys(all), []int{10, 15}; !slices.Equal(got, want) {
+		t.Errorf("All() built earlier yielded %v, want %v", got, want)
+	}
+	if got, want := keys(inRange), []int{10, 15}; !slices.Equal(got, want) {
+		t.Errorf("Range(10, 30) built earlier yielded %v, want %v", got, want)
+	}
+}
+
+// Examples run one at a time: leakcheck compares goroutine snapshots, so a
+// parallel test would look like a leak.
+func TestExamplesDoNotLeakOrDeadlock(t *testing.T) {
+	leakcheck.Check(t,
+		leakcheck.Example{Name: "letterFrequenciesOrdered", Fn: func() { letterFrequenciesOrdered("mississippi") }},
+		leakcheck.Example{Name: "orderedMapOperations", Fn: orderedMapOperations},
+		leakcheck.Example{Name: "orderedJSON", Fn: orderedJSON},
+		leakcheck.Example{Name: "sortedMapQueries", Fn: sortedMapQueries},
+		leakcheck.Example{Name: "timeSeries", Fn: timeSeries},
+	)
+}
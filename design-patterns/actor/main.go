@@ -0,0 +1,589 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"sugar/panics"
+)
+
+// --- 1. The Actor Contract ---
+
+// Handler processes the messages of one actor. Only the actor's own
+// goroutine calls Receive, so the handler's state needs no locks.
+type Handler[M any] interface {
+	Receive(self *Ref[M], msg M)
+}
+
+// Optional lifecycle hooks. A Handler implements the ones it cares about.
+type (
+	// Starter is called before the first message, and after every restart.
+	Starter interface{ PreStart() }
+	// Stopper is called when the actor stops, and on the old instance before a restart.
+	Stopper interface{ PostStop() }
+)
+
+// Props describes how to create an actor.
+type Props[M any] struct {
+	Name string
+
+	// New creates a fresh handler. It is called again on every restart,
+	// so a crashed actor never continues with half-updated state.
+	New func() Handler[M]
+
+	// Mailbox is the mailbox capacity. Zero means unbounded.
+	Mailbox int
+}
+
+// --- 2. The Mailbox ---
+
+// ErrMailboxFull is returned by Send when a bounded mailbox has no room.
+var ErrMailboxFull = errors.New("actor: mailbox full")
+
+// ErrStopped is returned when sending to an actor that has stopped.
+var ErrStopped = errors.New("actor: stopped")
+
+// mailbox is a FIFO queue. A slice behind a mutex (instead of a channel)
+// is what lets it be unbounded.
+type mailbox[M any] struct {
+	mu       sync.Mutex
+	queue    []M
+	capacity int           // 0 = unbounded
+	notify   chan struct{} // Signalled (non-blocking) on every push
+}
+
+func newMailbox[M any](capacity int) *mailbox[M] {
+	return &mailbox[M]{capacity: capacity, notify: make(chan struct{}, 1)}
+}
+
+func (m *mailbox[M]) push(msg M) error {
+	m.mu.Lock()
+	if m.capacity > 0 && len(m.queue) >= m.capacity {
+		m.mu.Unlock()
+		return ErrMailboxFull
+	}
+	m.queue = append(m.queue, msg)
+	m.mu.Unlock()
+
+	select {
+	case m.notify <- struct{}{}:
+	default: // A wake-up is already pending
+	}
+	return nil
+}
+
+func (m *mailbox[M]) pop() (M, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var zero M
+	if len(m.queue) == 0 {
+		return zero, false
+	}
+	msg := m.queue[0]
+	m.queue[0] = zero // Let the GC collect the message
+	m.queue = m.queue[1:]
+	return msg, true
+}
+
+// --- 3. The Actor Reference and Cell ---
+
+type directive int
+
+const (
+	restart directive = iota
+	stop
+)
+
+// Ref is the handle other code uses to talk to an actor.
+type Ref[M any] struct {
+	cell *cell[M]
+}
+
+// Name returns the actor's name from its Props.
+func (r *Ref[M]) Name() string {
+	return r.cell.props.Name
+}
+
+// Send puts msg in the actor's mailbox without waiting for it to be processed.
+func (r *Ref[M]) Send(msg M) error {
+	select {
+	case <-r.cell.done:
+		return ErrStopped
+	default:
+	}
+	return r.cell.mbox.push(msg)
+}
+
+// Stop asks the actor to stop and waits until it has.
+// Messages still in the mailbox are discarded.
+func (r *Ref[M]) Stop() {
+	r.cell.directive(stop)
+	<-r.cell.done
+}
+
+// Done is closed once the actor has stopped for good.
+func (r *Ref[M]) Done() <-chan struct{} {
+	return r.cell.done
+}
+
+// Ask sends a message built around a reply channel and waits for the answer.
+func Ask[M, R any](ctx context.Context, ref *Ref[M], build func(reply chan<- R) M) (R, error) {
+	var zero R
+
+	reply := make(chan R, 1) // Buffered: a late reply must not block the actor
+	if err := ref.Send(build(reply)); err != nil {
+		return zero, err
+	}
+
+	select {
+	case r := <-reply:
+		return r, nil
+	case <-ref.cell.done:
+		return zero, ErrStopped
+	case <-ctx.Done():
+		return zero, ctx.Err()
+	}
+}
+
+// child is the untyped view a supervisor has of its actors,
+// which may all have different message types.
+type child interface {
+	name() string
+	directive(d directive)
+	stopped() <-chan struct{}
+}
+
+// cell is the running actor: mailbox, current handler and goroutine.
+type cell[M any] struct {
+	props   Props[M]
+	mbox    *mailbox[M]
+	sup     *Supervisor
+	handler Handler[M]
+	ref     *Ref[M]
+
+	control chan directive
+	done    chan struct{}
+}
+
+func (c *cell[M]) name() string             { return c.props.Name }
+func (c *cell[M]) stopped() <-chan struct{} { return c.done }
+
+// directive never blocks: the supervisor calls it for siblings that may be
+// stuck reporting their own crash to it. A pending restart already covers
+// another restart, and a stop replaces it.
+func (c *cell[M]) directive(d directive) {
+	for {
+		select {
+		case c.control <- d:
+			return
+		case <-c.done:
+			return
+		default:
+		}
+
+		if d == restart {
+			return
+		}
+		select {
+		case <-c.control: // Drop the pending directive and try again
+		default:
+		}
+	}
+}
+
+// Spawn starts an actor. With a nil supervisor a crash simply stops it.
+func Spawn[M any](sup *Supervisor, props Props[M]) *Ref[M] {
+	c := &cell[M]{
+		props:   props,
+		mbox:    newMailbox[M](props.Mailbox),
+		sup:     sup,
+		control: make(chan directive, 1),
+		done:    make(chan struct{}),
+	}
+	c.ref = &Ref[M]{cell: c}
+
+	if sup != nil {
+		sup.add(c)
+	}
+
+	go c.run()
+	return c.ref
+}
+
+func (c *cell[M]) run() {
+	defer close(c.done)
+
+	if reason, crashed := c.start(); crashed && !c.crashed(reason) {
+		return
+	}
+	for {
+		// Control directives win over queued messages
+		select {
+		case d := <-c.control:
+			if !c.apply(d) {
+				return
+			}
+			continue
+		default:
+		}
+
+		if msg, ok := c.mbox.pop(); ok {
+			if reason, crashed := c.invoke(msg); crashed && !c.crashed(reason) {
+				return
+			}
+			continue
+		}
+
+		select {
+		case <-c.mbox.notify:
+		case d := <-c.control:
+			if !c.apply(d) {
+				return
+			}
+		}
+	}
+}
+
+// start creates a fresh handler and runs its PreStart, turning a panic in
+// either into a crash report.
+func (c *cell[M]) start() (reason error, crashed bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			reason, crashed = panics.Recovered(r), true
+		}
+	}()
+
+	c.handler = c.props.New()
+	if s, ok := c.handler.(Starter); ok {
+		s.PreStart()
+	}
+	return nil, false
+}
+
+// postStop runs PostStop on the current handler, turning a panic into a
+// crash report. The handler is dropped first, so an instance is never
+// stopped twice - not even when its PostStop is what crashed.
+func (c *cell[M]) postStop() (reason error, crashed bool) {
+	h := c.handler
+	c.handler = nil
+
+	defer func() {
+		if r := recover(); r != nil {
+			reason, crashed = panics.Recovered(r), true
+		}
+	}()
+
+	if s, ok := h.(Stopper); ok {
+		s.PostStop()
+	}
+	return nil, false
+}
+
+// finish runs PostStop for an actor that is stopping for good. There is
+// nothing left to restart, so a panic is only logged.
+func (c *cell[M]) finish() {
+	if reason, crashed := c.postStop(); crashed {
+		fmt.Printf("[%s] PostStop panicked: %v\n", c.props.Name, reason)
+	}
+}
+
+// invoke delivers one message, turning a panic into a crash report.
+func (c *cell[M]) invoke(msg M) (reason error, crashed bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			reason, crashed = panics.Recovered(r), true
+		}
+	}()
+
+	c.handler.Receive(c.ref, msg)
+	return nil, false
+}
+
+// crashed asks the supervisor what to do and reports whether to keep running.
+func (c *cell[M]) crashed(reason error) bool {
+	if c.sup == nil {
+		fmt.Printf("[%s] crashed with no supervisor: %v\n", c.props.Name, reason)
+		c.finish()
+		return false
+	}
+
+	if !c.sup.report(failure{child: c, reason: reason}) {
+		c.finish() // Supervisor is gone
+		return false
+	}
+	return c.apply(<-c.control)
+}
+
+// apply executes a directive and reports whether to keep running.
+// A panic in the old instance's PostStop or the new one's New or PreStart
+// is a crash like any other and goes back to the supervisor.
+func (c *cell[M]) apply(d directive) bool {
+	if d == stop {
+		c.finish()
+		return false
+	}
+	if reason, crashed := c.postStop(); crashed {
+		return c.crashed(reason)
+	}
+	if reason, crashed := c.start(); crashed {
+		return c.crashed(reason)
+	}
+	return true
+}
+
+// --- 4. The Supervisor ---
+
+// Strategy decides which actors are restarted when one crashes.
+type Strategy int
+
+const (
+	OneForOne Strategy = iota // Restart only the crashed actor
+	OneForAll                 // Restart every actor under the supervisor
+)
+
+type failure struct {
+	child  child
+	reason error
+}
+
+// ErrTooManyRestarts is the supervisor's result when it gives up.
+var ErrTooManyRestarts = errors.New("actor: too many restarts")
+
+// Supervisor restarts crashed actors. If more than maxRestarts crashes
+// happen within window, it stops all of its actors and gives up.
+type Supervisor struct {
+	strategy    Strategy
+	maxRestarts int
+	window      time.Duration
+
+	mu       sync.Mutex
+	children []child
+
+	failures chan failure
+	quit     chan struct{}
+	quitOnce sync.Once
+	done     chan struct{}
+	err      error
+}
+
+// NewSupervisor creates and starts a supervisor.
+func NewSupervisor(strategy Strategy, maxRestarts int, window time.Duration) *Supervisor {
+	s := &Supervisor{
+		strategy:    strategy,
+		maxRestarts: maxRestarts,
+		window:      window,
+		failures:    make(chan failure),
+		quit:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+	go s.loop()
+	return s
+}
+
+func (s *Supervisor) add(c child) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.children = append(s.children, c)
+}
+
+// report hands a crash to the supervisor. It returns false if the supervisor has already given up.
+func (s *Supervisor) report(f failure) bool {
+	select {
+	case s.failures <- f:
+		return true
+	case <-s.quit:
+		return false
+	}
+}
+
+// Stop stops every actor under the supervisor, then the supervisor itself.
+func (s *Supervisor) Stop() {
+	s.quitOnce.Do(func() { close(s.quit) })
+	<-s.done
+}
+
+// Done is closed when the supervisor stops or gives up. Err then says why.
+func (s *Supervisor) Done() <-chan struct{} {
+	return s.done
+}
+
+// Err returns ErrTooManyRestarts if the supervisor gave up, nil otherwise.
+func (s *Supervisor) Err() error {
+	select {
+	case <-s.done:
+		return s.err
+	default:
+		return nil
+	}
+}
+
+func (s *Supervisor) loop() {
+	var recent []time.Time
+
+	for {
+		var f failure
+		select {
+		case f = <-s.failures:
+		case <-s.quit:
+			s.shutdown(nil)
+			return
+		}
+
+		now := time.Now()
+		recent = append(recent, now)
+
+		// Forget crashes that fell out of the window
+		for len(recent) > 0 && now.Sub(recent[0]) > s.window {
+			recent = recent[1:]
+		}
+
+		if len(recent) > s.maxRestarts {
+			fmt.Printf("[supervisor] %s crashed %d times within %s, giving up\n", f.child.name(), len(recent), s.window)
+			s.shutdown(ErrTooManyRestarts)
+			return
+		}
+
+		fmt.Printf("[supervisor] %s crashed (%v), restarting\n", f.child.name(), f.reason)
+		f.child.directive(restart)
+
+		if s.strategy == OneForAll {
+			for _, c := range s.siblings(f.child) {
+				c.directive(restart)
+			}
+		}
+	}
+}
+
+func (s *Supervisor) siblings(of child) []child {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var out []child
+	for _, c := range s.children {
+		if c != of {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// shutdown stops every child. A child that just crashed is waiting for a
+// directive; the others pick the stop up from their control channels.
+func (s *Supervisor) shutdown(err error) {
+	s.quitOnce.Do(func() { close(s.quit) }) // From now on report returns false
+
+	children := s.siblings(nil)
+	for _, c := range children {
+		c.directive(stop)
+	}
+	for _, c := range children {
+		<-c.stopped()
+	}
+
+	s.err = err
+	close(s.done)
+}
+
+// --- 5. Concrete Actors ---
+
+// CounterMsg is the message type of the counter actor.
+type CounterMsg struct {
+	Op    string // "inc", "get" or "boom"
+	Reply chan<- int
+}
+
+type counter struct {
+	name  string
+	count int
+}
+
+func (c *counter) PreStart() { fmt.Printf("[%s] started with count %d\n", c.name, c.count) }
+func (c *counter) PostStop() { fmt.Printf("[%s] stopped at count %d\n", c.name, c.count) }
+
+func (c *counter) Receive(self *Ref[CounterMsg], msg CounterMsg) {
+	switch msg.Op {
+	case "inc":
+		c.count++
+	case "get":
+		msg.Reply <- c.count
+	case "boom":
+		panic("counter exploded")
+	}
+}
+
+func counterProps(name string) Props[CounterMsg] {
+	return Props[CounterMsg]{
+		Name:    name,
+		New:     func() Handler[CounterMsg] { return &counter{name: name} },
+		Mailbox: 16,
+	}
+}
+
+func get(ref *Ref[CounterMsg]) int {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	n, err := Ask(ctx, ref, func(reply chan<- int) CounterMsg {
+		return CounterMsg{Op: "get", Reply: reply}
+	})
+	if err != nil {
+		fmt.Println("Ask failed:", err)
+	}
+	return n
+}
+
+// --- 6. Client Code (Demonstration) ---
+
+func main() {
+	// --- Example 1: Send and Ask ---
+	fmt.Println("--- Send and Ask ---")
+	plain := Spawn(nil, counterProps("plain"))
+	for i := 0; i < 3; i++ {
+		plain.Send(CounterMsg{Op: "inc"})
+	}
+	fmt.Println("Count:", get(plain))
+	plain.Stop()
+
+	// --- Example 2: One-for-one restart ---
+	fmt.Println("\n--- One-for-one supervision ---")
+	sup := NewSupervisor(OneForOne, 3, time.Second)
+	a := Spawn(sup, counterProps("a"))
+	b := Spawn(sup, counterProps("b"))
+
+	a.Send(CounterMsg{Op: "inc"})
+	b.Send(CounterMsg{Op: "inc"})
+	a.Send(CounterMsg{Op: "boom"}) // Only 'a' is restarted, with fresh state
+	a.Send(CounterMsg{Op: "inc"})  // Still in the mailbox, survives the restart
+
+	fmt.Printf("a=%d b=%d\n", get(a), get(b))
+	sup.Stop()
+
+	// --- Example 3: One-for-all restart ---
+	fmt.Println("\n--- One-for-all supervision ---")
+	sup = NewSupervisor(OneForAll, 3, time.Second)
+	x := Spawn(sup, counterProps("x"))
+	y := Spawn(sup, counterProps("y"))
+
+	y.Send(CounterMsg{Op: "inc"})
+	fmt.Printf("before crash: y=%d\n", get(y))
+	x.Send(CounterMsg{Op: "boom"}) // Both 'x' and 'y' are restarted
+	time.Sleep(50 * time.Millisecond)
+	fmt.Printf("after crash:  y=%d\n", get(y))
+	sup.Stop()
+
+	// --- Example 4: Too many restarts ---
+	fmt.Println("\n--- Restart limit ---")
+	sup = NewSupervisor(OneForOne, 2, time.Second)
+	flaky := Spawn(sup, counterProps("flaky"))
+	for i := 0; i < 3; i++ {
+		flaky.Send(CounterMsg{Op: "boom"})
+	}
+
+	<-sup.Done()
+	fmt.Println("Supervisor error:", sup.Err())
+	fmt.Println("Send after give-up:", flaky.Send(CounterMsg{Op: "inc"}))
+}
@@ -0,0 +1,238 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"sugar/channels/leakcheck"
+	"sugar/panics"
+)
+
+// hooks is a counter whose lifecycle hooks panic on demand.
+type hooks struct {
+	counter
+	preStartPanics bool
+	postStopPanics bool
+}
+
+func (h *hooks) PreStart() {
+	if h.preStartPanics {
+		panic("PreStart exploded")
+	}
+}
+
+func (h *hooks) PostStop() {
+	if h.postStopPanics {
+		panic("PostStop exploded")
+	}
+}
+
+// flakyProps passes newHandler the number of the New call, so it can fail
+// the first few and succeed after a restart.
+func flakyProps(newHandler func(call int64) Handler[CounterMsg]) Props[CounterMsg] {
+	var calls atomic.Int64
+	return Props[CounterMsg]{
+		Name: "flaky",
+		New: func() Handler[CounterMsg] {
+			return newHandler(calls.Add(1))
+		},
+	}
+}
+
+func askCount(t *testing.T, ref *Ref[CounterMsg]) (int, error) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	return Ask(ctx, ref, func(reply chan<- int) CounterMsg {
+		return CounterMsg{Op: "get", Reply: reply}
+	})
+}
+
+func TestPanicInNewIsRestarted(t *testing.T) {
+	sup := NewSupervisor(OneForOne, 3, time.Second)
+	defer sup.Stop()
+
+	ref := Spawn(sup, flakyProps(func(call int64) Handler[CounterMsg] {
+		if call <= 2 {
+			panic("New exploded")
+		}
+		return &counter{name: "flaky"}
+	}))
+
+	if _, err := askCount(t, ref); err != nil {
+		t.Fatalf("Ask after New panics = %v, want the restarted actor to answer", err)
+	}
+}
+
+func TestPanicInPreStartIsRestarted(t *testing.T) {
+	sup := NewSupervisor(OneForOne, 3, time.Second)
+	defer sup.Stop()
+
+	ref := Spawn(sup, flakyProps(func(call int64) Handler[CounterMsg] {
+		return &hooks{preStartPanics: call == 1}
+	}))
+
+	if _, err := askCount(t, ref); err != nil {
+		t.Fatalf("Ask after PreStart panic = %v, want the restarted actor to answer", err)
+	}
+}
+
+func TestPanicInPostStopOnRestartIsReported(t *testing.T) {
+	sup := NewSupervisor(OneForOne, 3, time.Second)
+	defer sup.Stop()
+
+	ref := Spawn(sup, flakyProps(func(call int64) Handler[CounterMsg] {
+		return &hooks{postStopPanics: call == 1}
+	}))
+
+	ref.Send(CounterMsg{Op: "boom"}) // Restart runs the panicking PostStop
+	if _, err := askCount(t, ref); err != nil {
+		t.Fatalf("Ask after PostStop panic = %v, want the restarted actor to answer", err)
+	}
+}
+
+func TestPanicInPostStopOnStop(t *testing.T) {
+	ref := Spawn(nil, Props[CounterMsg]{
+		Name: "stopper",
+		New:  func() Handler[CounterMsg] { return &hooks{postStopPanics: true} },
+	})
+	ref.Stop() // Must not take the test binary down
+}
+
+func TestPanicInNewWithoutSupervisorStops(t *testing.T) {
+	ref := Spawn(nil, Props[CounterMsg]{
+		Name: "broken",
+		New:  func() Handler[CounterMsg] { panic("New exploded") },
+	})
+
+	select {
+	case <-ref.Done():
+	case <-time.After(time.Second):
+		t.Fatal("actor did not stop after New panicked")
+	}
+	if err := ref.Send(CounterMsg{Op: "inc"}); !errors.Is(err, ErrStopped) {
+		t.Fatalf("Send = %v, want ErrStopped", err)
+	}
+}
+
+func TestNewAlwaysPanickingGivesUp(t *testing.T) {
+	sup := NewSupervisor(OneForOne, 2, time.Second)
+	Spawn(sup, Props[CounterMsg]{
+		Name: "hopeless",
+		New:  func() Handler[CounterMsg] { panic("New exploded") },
+	})
+
+	select {
+	case <-sup.Done():
+	case <-time.After(time.Second):
+		t.Fatal("supervisor did not give up")
+	}
+	if !errors.Is(sup.Err(), ErrTooManyRestarts) {
+		t.Fatalf("Err() = %v, want ErrTooManyRestarts", sup.Err())
+	}
+}
+
+func TestCrashIsReportedToPanicsRegistry(t *testing.T) {
+	sup := NewSupervisor(OneForOne, 3, time.Second)
+	defer sup.Stop()
+
+	ref := Spawn(sup, flakyProps(func(call int64) Handler[CounterMsg] {
+		if call == 1 {
+			panic("New exploded once")
+		}
+		return &counter{name: "flaky"}
+	}))
+	if _, err := askCount(t, ref); err != nil {
+		t.Fatalf("Ask after restart: %v", err)
+	}
+
+	for _, e := range panics.Default.Snapshot().Entries {
+		if e.Sample == "New exploded once" {
+			return
+		}
+	}
+	t.Fatal("the crash in New was not reported to panics.Default")
+}
+
+func TestOneForAllOverlappingCrashes(t *testing.T) {
+	sup := NewSupervisor(OneForAll, 1000, time.Minute)
+	refs := make([]*Ref[CounterMsg], 3)
+	for i, name := range []string{"x", "y", "z"} {
+		refs[i] = Spawn(sup, counterProps(name))
+	}
+
+	// Every actor crashes at about the same time, several rounds in a row,
+	// so siblings report while the supervisor is restarting them
+	for range 5 { // Stays within the 16-message mailboxes
+		for _, ref := range refs {
+			ref.Send(CounterMsg{Op: "boom"})
+		}
+	}
+
+	leakcheck.WithDeadlockTimeout(t, 5*time.Second, func() {
+		for _, ref := range refs {
+			ref.Send(CounterMsg{Op: "inc"})
+			if _, err := askCount(t, ref); err != nil {
+				t.Errorf("%s: Ask after the crashes = %v", ref.Name(), err)
+			}
+		}
+		sup.Stop()
+	})
+	if err := sup.Err(); err != nil {
+		t.Fatalf("Err() = %v, want nil after Stop", err)
+	}
+}
+
+func TestRestartWindow(t *testing.T) {
+	t.Run("too many crashes within the window", func(t *testing.T) {
+		sup := NewSupervisor(OneForOne, 2, time.Minute)
+		ref := Spawn(sup, counterProps("flaky"))
+		other := Spawn(sup, counterProps("other"))
+		for range 3 {
+			ref.Send(CounterMsg{Op: "boom"})
+		}
+
+		select {
+		case <-sup.Done():
+		case <-time.After(time.Second):
+			t.Fatal("supervisor did not give up after 3 crashes with maxRestarts 2")
+		}
+		if !errors.Is(sup.Err(), ErrTooManyRestarts) {
+			t.Fatalf("Err() = %v, want ErrTooManyRestarts", sup.Err())
+		}
+		// Giving up stops every actor, not only the one that crashed
+		for _, r := range []*Ref[CounterMsg]{ref, other} {
+			if err := r.Send(CounterMsg{Op: "inc"}); !errors.Is(err, ErrStopped) {
+				t.Errorf("%s: Send after give-up = %v, want ErrStopped", r.Name(), err)
+			}
+		}
+	})
+
+	t.Run("crashes spread out are forgiven", func(t *testing.T) {
+		sup := NewSupervisor(OneForOne, 1, 20*time.Millisecond)
+		defer sup.Stop()
+		ref := Spawn(sup, counterProps("flaky"))
+
+		for range 3 {
+			ref.Send(CounterMsg{Op: "boom"})
+			if _, err := askCount(t, ref); err != nil {
+				t.Fatalf("Ask after a crash = %v", err)
+			}
+			time.Sleep(50 * time.Millisecond) // Let the crash fall out of the window
+		}
+		if err := sup.Err(); err != nil {
+			t.Fatalf("Err() = %v, want nil when crashes are further apart than the window", err)
+		}
+	})
+}
+
+// Examples run one at a time: leakcheck compares goroutine snapshots, so a
+// parallel test would look like a leak.
+func TestExamplesDoNotLeakOrDeadlock(t *testing.T) {
+	leakcheck.Check(t,
+		leakcheck.Example{Name: "main", Fn: main},
+	)
+}
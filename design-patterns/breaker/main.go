@@ -0,0 +1,457 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"sugar/clock"
+)
+
+// --- 1. States ---
+
+// State is the position of the circuit breaker.
+type State int
+
+const (
+	Closed   State = iota // Requests flow normally; failures are counted
+	Open                  // Requests fail fast with ErrOpen until the cooldown ends
+	HalfOpen              // A few probe requests test whether the dependency recovered
+)
+
+func (s State) String() string {
+	switch s {
+	case Closed:
+		return "closed"
+	case Open:
+		return "open"
+	case HalfOpen:
+		return "half-open"
+	}
+	return fmt.Sprintf("State(%d)", int(s))
+}
+
+// ErrOpen matches every *OpenError, so callers can test with errors.Is(err, ErrOpen).
+var ErrOpen = errors.New("circuit breaker is open")
+
+// OpenError is returned instead of calling the dependency while the breaker
+// is open, or while half-open and all probe slots are taken.
+type OpenError struct {
+	Name       string
+	RetryAfter time.Duration // Time left in the cooldown; 0 while half-open
+}
+
+func (e *OpenError) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("%s: circuit breaker is open (retry after %s)", e.Name, e.RetryAfter)
+	}
+	return fmt.Sprintf("%s: circuit breaker is open (probes in flight)", e.Name)
+}
+
+func (e *OpenError) Is(target error) bool { return target == ErrOpen }
+
+// --- 2. Settings ---
+
+// Settings configures when the breaker trips and how it recovers.
+// A zero field takes the default noted next to it.
+type Settings struct {
+	// Trip after this many failures in a row (default 5).
+	ConsecutiveFailures int
+
+	// Or trip when the failure rate over Window reaches FailureRate,
+	// once at least MinRequests were made in the window. 0 disables it.
+	FailureRate float64
+	Window      time.Duration // default 10s, at least 10ns (one per bucket)
+	MinRequests int           // default 10
+
+	Cooldown       time.Duration // How long to stay open (default 5s)
+	HalfOpenProbes int           // Probes that must all succeed to close again (default 1)
+
+	// OnStateChange runs with the breaker locked, so it must not call back into it.
+	OnStateChange func(from, to State)
+
+	// Clock lets tests control time. Defaults to clock.Real.
+	Clock clock.Clock
+}
+
+func (s *Settings) defaults() {
+	if s.ConsecutiveFailures <= 0 {
+		s.ConsecutiveFailures = 5
+	}
+	if s.Window <= 0 {
+		s.Window = 10 * time.Second
+	}
+	if s.MinRequests <= 0 {
+		s.MinRequests = 10
+	}
+	if s.Cooldown <= 0 {
+		s.Cooldown = 5 * time.Second
+	}
+	if s.HalfOpenProbes <= 0 {
+		s.HalfOpenProbes = 1
+	}
+	if s.Clock == nil {
+		s.Clock = clock.Real
+	}
+}
+
+// --- 3. Rolling Window ---
+
+const numBuckets = 10
+
+// window counts successes and failures over the last Window, in buckets of
+// Window/10, so old results age out without storing every request.
+type window struct {
+	size    time.Duration
+	buckets [numBuckets]struct {
+		start             time.Time
+		success, failures int
+	}
+}
+
+func (w *window) bucket(now time.Time) int {
+	width := w.size / numBuckets
+	start := now.Truncate(width)
+	i := int(start.UnixNano()/int64(width)) % numBuckets
+	if !w.buckets[i].start.Equal(start) {
+		w.buckets[i].start = start // Recycle a bucket that has aged out
+		w.buckets[i].success, w.buckets[i].failures = 0, 0
+	}
+	return i
+}
+
+func (w *window) record(now time.Time, ok bool) {
+	i := w.bucket(now)
+	if ok {
+		w.buckets[i].success++
+	} else {
+		w.buckets[i].failures++
+	}
+}
+
+func (w *window) totals(now time.Time) (total, failures int) {
+	for _, b := range w.buckets {
+		if now.Sub(b.start) < w.size {
+			total += b.success + b.failures
+			failures += b.failures
+		}
+	}
+	return total, failures
+}
+
+func (w *window) reset() {
+	w.buckets = [numBuckets]struct {
+		start             time.Time
+		success, failures int
+	}{}
+}
+
+// --- 4. The Breaker ---
+
+// Breaker stops calls to a failing dependency so it gets time to recover,
+// instead of every caller piling more load onto it.
+type Breaker struct {
+	name     string
+	settings Settings
+
+	mu          sync.Mutex
+	state       State
+	generation  uint64 // Bumped on every state change; stale results are ignored
+	consecutive int
+	window      window
+	openedAt    time.Time
+	probes      int // Probes in flight while half-open
+	probeOK     int // Successful probes while half-open
+}
+
+// NewBreaker creates a closed breaker. It panics if settings.Window is too
+// short to split into buckets.
+func NewBreaker(name string, settings Settings) *Breaker {
+	settings.defaults()
+	if settings.Window < numBuckets {
+		panic(fmt.Sprintf("breaker: Window must be at least %s, got %s", time.Duration(numBuckets), settings.Window))
+	}
+	return &Breaker{
+		name:     name,
+		settings: settings,
+		window:   window{size: settings.Window},
+	}
+}
+
+// State returns the current state, moving from open to half-open if the
+// cooldown has passed.
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.checkCooldown(b.settings.Clock.Now())
+	return b.state
+}
+
+// Execute runs fn if the breaker allows it and records the result.
+// A panic in fn counts as a failure and is then re-raised, so a half-open
+// probe that panics can't leave its slot taken forever.
+func (b *Breaker) Execute(fn func() error) error {
+	gen, err := b.allow()
+	if err != nil {
+		return err
+	}
+
+	ok := false
+	defer func() { b.record(gen, ok) }()
+
+	err = fn()
+	ok = err == nil
+	return err
+}
+
+func (b *Breaker) allow() (uint64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.settings.Clock.Now()
+	b.checkCooldown(now)
+
+	switch b.state {
+	case Open:
+		return 0, &OpenError{Name: b.name, RetryAfter: b.openedAt.Add(b.settings.Cooldown).Sub(now)}
+	case HalfOpen:
+		if b.probes >= b.settings.HalfOpenProbes {
+			return 0, &OpenError{Name: b.name}
+		}
+		b.probes++
+	}
+	return b.generation, nil
+}
+
+func (b *Breaker) record(gen uint64, ok bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if gen != b.generation {
+		return // Started before the last state change - no longer relevant
+	}
+	now := b.settings.Clock.Now()
+
+	switch b.state {
+	case Closed:
+		b.window.record(now, ok)
+		if ok {
+			b.consecutive = 0
+			return
+		}
+		b.consecutive++
+		if b.shouldTrip(now) {
+			b.setState(Open, now)
+		}
+
+	case HalfOpen:
+		if !ok {
+			b.setState(Open, now) // Still broken, back to waiting
+			return
+		}
+		b.probeOK++
+		if b.probeOK >= b.settings.HalfOpenProbes {
+			b.setState(Closed, now)
+		}
+	}
+}
+
+func (b *Breaker) shouldTrip(now time.Time) bool {
+	if b.consecutive >= b.settings.ConsecutiveFailures {
+		return true
+	}
+	if b.settings.FailureRate > 0 {
+		total, failures := b.window.totals(now)
+		if total >= b.settings.MinRequests && float64(failures)/float64(total) >= b.settings.FailureRate {
+			return true
+		}
+	}
+	return false
+}
+
+// checkCooldown must be called with b.mu held.
+func (b *Breaker) checkCooldown(now time.Time) {
+	if b.state == Open && now.Sub(b.openedAt) >= b.settings.Cooldown {
+		b.setState(HalfOpen, now)
+	}
+}
+
+// setState must be called with b.mu held.
+func (b *Breaker) setState(to State, now time.Time) {
+	from := b.state
+	if from == to {
+		return
+	}
+
+	b.state = to
+	b.generation++
+	b.consecutive = 0
+	b.probes, b.probeOK = 0, 0
+	b.window.reset()
+	if to == Open {
+		b.openedAt = now
+	}
+
+	if b.settings.OnStateChange != nil {
+		b.settings.OnStateChange(from, to)
+	}
+}
+
+// --- 5. Wrapping StorageService as a Proxy ---
+
+// StorageService is the interface from the proxy example.
+type StorageService interface {
+	DeleteFile(filename string, userID string) error
+	ReadFile(filename string) (string, error)
+}
+
+// FlakyStorageService is a real service whose backend can go down.
+type FlakyStorageService struct {
+	Down  bool
+	Calls int
+}
+
+func (s *FlakyStorageService) DeleteFile(filename string, userID string) error {
+	s.Calls++
+	if s.Down {
+		return fmt.Errorf("storage backend unavailable")
+	}
+	return nil
+}
+
+func (s *FlakyStorageService) ReadFile(filename string) (string, error) {
+	s.Calls++
+	if s.Down {
+		return "", fmt.Errorf("storage backend unavailable")
+	}
+	return fmt.Sprintf("Content of %s", filename), nil
+}
+
+// BreakerProxy guards every StorageService call with a circuit breaker.
+// Callers keep using the StorageService interface and don't know it's there.
+type BreakerProxy struct {
+	realService StorageService
+	breaker     *Breaker
+}
+
+// NewBreakerProxy creates a new proxy instance.
+func NewBreakerProxy(service StorageService, breaker *Breaker) *BreakerProxy {
+	return &BreakerProxy{realService: service, breaker: breaker}
+}
+
+// DeleteFile implements the StorageService interface.
+func (p *BreakerProxy) DeleteFile(filename string, userID string) error {
+	return p.breaker.Execute(func() error {
+		return p.realService.DeleteFile(filename, userID)
+	})
+}
+
+// ReadFile implements the StorageService interface.
+func (p *BreakerProxy) ReadFile(filename string) (string, error) {
+	var content string
+	err := p.breaker.Execute(func() error {
+		var err error
+		content, err = p.realService.ReadFile(filename)
+		return err
+	})
+	return content, err
+}
+
+// --- 6. Composing With a Retry Loop ---
+
+// readWithRetry is retryPattern on top of the breaker: it retries ordinary
+// failures, but gives up at once on ErrOpen instead of hammering the dependency.
+func readWithRetry(svc StorageService, filename string, maxRetries int) (string, error) {
+	var err error
+	for attempt := 1; attempt <= maxRetries; attempt++ {
+		var content string
+		content, err = svc.ReadFile(filename)
+		if err == nil {
+			return content, nil
+		}
+		var open *OpenError
+		if errors.As(err, &open) {
+			fmt.Printf("  Attempt %d: %v - not retrying\n", attempt, err)
+			return "", err
+		}
+		fmt.Printf("  Attempt %d failed: %v\n", attempt, err)
+	}
+	return "", fmt.Errorf("all %d retries exhausted: %w", maxRetries, err)
+}
+
+// --- 7. Client Code (Demonstration) ---
+
+func main() {
+	// A hand-driven clock makes the cooldown instant
+	clk := clock.NewFakeClock(time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC))
+
+	breaker := NewBreaker("storage", Settings{
+		ConsecutiveFailures: 3,
+		Cooldown:            30 * time.Second,
+		HalfOpenProbes:      2,
+		OnStateChange: func(from, to State) {
+			fmt.Printf("  [breaker] %s -> %s\n", from, to)
+		},
+		Clock: clk,
+	})
+
+	backend := &FlakyStorageService{}
+	proxy := NewBreakerProxy(backend, breaker)
+
+	fmt.Println("--- Backend healthy ---")
+	content, err := readWithRetry(proxy, "public_log.txt", 3)
+	fmt.Println("Read:", content, err)
+
+	fmt.Println("\n--- Backend goes down ---")
+	backend.Down = true
+	_, err = readWithRetry(proxy, "public_log.txt", 5)
+	fmt.Println("Error:", err)
+
+	fmt.Println("\n--- While open, calls fail fast without touching the backend ---")
+	before := backend.Calls
+	for i := 0; i < 100; i++ {
+		proxy.DeleteFile("secret_data.txt", "admin")
+	}
+	fmt.Printf("100 calls, backend hit %d times\n", backend.Calls-before)
+
+	fmt.Println("\n--- Cooldown passes, but backend is still down ---")
+	clk.Advance(30 * time.Second)
+	fmt.Println("State:", breaker.State())
+	_, err = proxy.ReadFile("public_log.txt")
+	fmt.Println("Probe:", err)
+
+	fmt.Println("\n--- Backend recovers ---")
+	backend.Down = false
+	clk.Advance(30 * time.Second)
+	for i := 1; i <= 2; i++ {
+		_, err = proxy.ReadFile("public_log.txt")
+		fmt.Printf("Probe %d: err=%v\n", i, err)
+	}
+	fmt.Println("State:", breaker.State())
+
+	fmt.Println("\n--- Failure-rate trigger ---")
+	rate := NewBreaker("search", Settings{
+		ConsecutiveFailures: 100, // Effectively off: only the rate matters here
+		FailureRate:         0.5,
+		MinRequests:         10,
+		Window:              10 * time.Second,
+		OnStateChange: func(from, to State) {
+			fmt.Printf("  [breaker] %s -> %s\n", from, to)
+		},
+		Clock: clk,
+	})
+	failing := errors.New("timeout")
+	for i := 1; i <= 12; i++ {
+		err := rate.Execute(func() error {
+			if i%2 == 0 {
+				return failing // Every other call fails: 50%
+			}
+			return nil
+		})
+		if errors.Is(err, ErrOpen) {
+			fmt.Printf("Call %d rejected: %v\n", i, err)
+		}
+		clk.Advance(100 * time.Millisecond)
+	}
+}
@@ -0,0 +1,237 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"sugar/channels/leakcheck"
+	"sugar/clock"
+)
+
+var errDown = errors.New("down")
+
+func fail() error    { return errDown }
+func succeed() error { return nil }
+
+func newFakeClock() *clock.FakeClock {
+	return clock.NewFakeClock(time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC))
+}
+
+func TestConsecutiveFailuresTrip(t *testing.T) {
+	b := NewBreaker("test", Settings{ConsecutiveFailures: 3, Clock: newFakeClock()})
+
+	// A success in between resets the streak
+	b.Execute(fail)
+	b.Execute(fail)
+	b.Execute(succeed)
+	b.Execute(fail)
+	b.Execute(fail)
+	if got := b.State(); got != Closed {
+		t.Fatalf("State() = %s after a broken streak, want %s", got, Closed)
+	}
+
+	b.Execute(fail)
+	if got := b.State(); got != Open {
+		t.Fatalf("State() = %s after 3 failures in a row, want %s", got, Open)
+	}
+}
+
+func TestOpenErrorMatchesErrOpen(t *testing.T) {
+	clk := newFakeClock()
+	b := NewBreaker("storage", Settings{ConsecutiveFailures: 1, Cooldown: 30 * time.Second, Clock: clk})
+	b.Execute(fail)
+	clk.Advance(10 * time.Second)
+
+	called := false
+	err := b.Execute(func() error { called = true; return nil })
+	if called {
+		t.Error("Execute called fn while open")
+	}
+	if !errors.Is(err, ErrOpen) {
+		t.Fatalf("errors.Is(%v, ErrOpen) = false", err)
+	}
+	wrapped := fmt.Errorf("read config: %w", err)
+	var open *OpenError
+	if !errors.As(wrapped, &open) || open.Name != "storage" || open.RetryAfter != 20*time.Second {
+		t.Fatalf("errors.As(%v) = %+v, want storage with 20s left", wrapped, open)
+	}
+	if errors.Is(errDown, ErrOpen) {
+		t.Error("an ordinary error matches ErrOpen")
+	}
+}
+
+func TestFailureRateOverRollingWindow(t *testing.T) {
+	clk := newFakeClock()
+	b := NewBreaker("test", Settings{
+		ConsecutiveFailures: 100, // Only the rate matters here
+		FailureRate:         0.5,
+		MinRequests:         4,
+		Window:              10 * time.Second,
+		Clock:               clk,
+	})
+
+	// Three failures, then a full window later three successes: the
+	// failures have aged out, so the rate is 1/4, not 4/7
+	for range 3 {
+		b.Execute(fail)
+	}
+	clk.Advance(10 * time.Second)
+	for range 3 {
+		b.Execute(succeed)
+	}
+	b.Execute(fail)
+	if got := b.State(); got != Closed {
+		t.Fatalf("State() = %s with old failures outside the window, want %s", got, Closed)
+	}
+
+	// Below MinRequests nothing trips, whatever the rate
+	b2 := NewBreaker("test", Settings{ConsecutiveFailures: 100, FailureRate: 0.5, MinRequests: 4, Clock: clk})
+	for range 3 {
+		b2.Execute(fail)
+	}
+	if got := b2.State(); got != Closed {
+		t.Fatalf("State() = %s below MinRequests, want %s", got, Closed)
+	}
+
+	// Within the window: 2 of 5 failed is still below 50%, 3 of 6 reaches it
+	b.Execute(fail)
+	if got := b.State(); got != Closed {
+		t.Fatalf("State() = %s at a 40%% failure rate, want %s", got, Closed)
+	}
+	b.Execute(fail)
+	if got := b.State(); got != Open {
+		t.Fatalf("State() = %s at a 50%% failure rate, want %s", got, Open)
+	}
+}
+
+func TestHalfOpenProbes(t *testing.T) {
+	clk := newFakeClock()
+	b := NewBreaker("test", Settings{
+		ConsecutiveFailures: 1,
+		Cooldown:            time.Second,
+		HalfOpenProbes:      2,
+		Clock:               clk,
+	})
+	b.Execute(fail)
+	clk.Advance(time.Second)
+
+	// Two probes may run at once; a third is rejected while they are in flight
+	release := make(chan struct{})
+	results := make(chan error, 2)
+	entered := make(chan struct{}, 2)
+	for range 2 {
+		go func() {
+			results <- b.Execute(func() error {
+				entered <- struct{}{}
+				<-release
+				return nil
+			})
+		}()
+	}
+	<-entered
+	<-entered
+	if err := b.Execute(succeed); !errors.Is(err, ErrOpen) {
+		t.Fatalf("third probe = %v, want ErrOpen while 2 are in flight", err)
+	}
+	close(release)
+	for range 2 {
+		if err := <-results; err != nil {
+			t.Fatalf("probe = %v", err)
+		}
+	}
+	if got := b.State(); got != Closed {
+		t.Fatalf("State() = %s after 2 successful probes, want %s", got, Closed)
+	}
+
+	// One successful probe out of two is not enough, and a failure reopens
+	b.Execute(fail)
+	clk.Advance(time.Second)
+	b.Execute(succeed)
+	if got := b.State(); got != HalfOpen {
+		t.Fatalf("State() = %s after 1 of 2 probes, want %s", got, HalfOpen)
+	}
+	b.Execute(fail)
+	if got := b.State(); got != Open {
+		t.Fatalf("State() = %s after a failed probe, want %s", got, Open)
+	}
+}
+
+func TestOnStateChange(t *testing.T) {
+	clk := newFakeClock()
+	var changes []string
+	b := NewBreaker("test", Settings{
+		ConsecutiveFailures: 1,
+		Cooldown:            time.Second,
+		Clock:               clk,
+		OnStateChange: func(from, to State) {
+			changes = append(changes, from.String()+"->"+to.String())
+		},
+	})
+
+	b.Execute(fail)
+	b.Execute(fail) // Rejected while open: no change
+	clk.Advance(time.Second)
+	b.Execute(fail) // Failed probe
+	clk.Advance(time.Second)
+	b.Execute(succeed)
+
+	want := []string{"closed->open", "open->half-open", "half-open->open", "open->half-open", "half-open->closed"}
+	if !slices.Equal(changes, want) {
+		t.Fatalf("transitions = %v, want %v", changes, want)
+	}
+}
+
+func TestWindowTooShortPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("NewBreaker accepted a 5ns Window")
+		}
+	}()
+	NewBreaker("test", Settings{FailureRate: 0.5, Window: 5 * time.Nanosecond})
+}
+
+func TestPanickingProbeIsRecordedAsFailure(t *testing.T) {
+	clk := newFakeClock()
+	b := NewBreaker("test", Settings{
+		ConsecutiveFailures: 1,
+		Cooldown:            time.Second,
+		HalfOpenProbes:      1,
+		Clock:               clk,
+	})
+
+	b.Execute(fail)
+	clk.Advance(time.Second)
+	if got := b.State(); got != HalfOpen {
+		t.Fatalf("State() = %s, want %s", got, HalfOpen)
+	}
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("Execute swallowed the panic")
+			}
+		}()
+		b.Execute(func() error { panic("probe exploded") })
+	}()
+
+	if got := b.State(); got != Open {
+		t.Fatalf("State() after a panicking probe = %s, want %s", got, Open)
+	}
+
+	// The probe slot was released: after the next cooldown a probe runs again
+	clk.Advance(time.Second)
+	if err := b.Execute(succeed); err != nil {
+		t.Fatalf("Execute() after cooldown = %v, want the probe to run", err)
+	}
+}
+
+// Examples run one at a time: leakcheck compares goroutine snapshots, so a
+// parallel test would look like a leak.
+func TestExamplesDoNotLeakOrDeadlock(t *testing.T) {
+	leakcheck.Check(t,
+		leakcheck.Example{Name: "main", Fn: main},
+	)
+}
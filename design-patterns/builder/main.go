@@ -1,6 +1,11 @@
 package main
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+
+	"sugar/errs"
+)
 
 // --- 1. The Target Struct (The Complex Object) ---
 
@@ -77,16 +82,17 @@ func (b *UserBuilder) WithRole(role string) *UserBuilder {
 
 // --- 4. The Finalizer Method ---
 
-// Build validates and constructs the final User struct.
+// Build validates and constructs the final User struct. Validation errors
+// are errs.InvalidArgument, so callers can tell bad input from a bug.
 func (b *UserBuilder) Build() (*User, error) {
 	// Perform validation checks before finalizing the object
 	if !b.hasFirstName || !b.hasLastName {
-		return nil, fmt.Errorf("required fields (first name and last name) are missing")
+		return nil, errs.New(errs.InvalidArgument, "required fields (first name and last name) are missing")
 	}
 
 	// Optional: Check if email is required if role is 'admin'
 	if b.user.role == "admin" && b.user.email == "" {
-		return nil, fmt.Errorf("admin user must have an email address")
+		return nil, errs.New(errs.InvalidArgument, "admin user must have an email address", "field", "email")
 	}
 
 	// Return a copy of the constructed user struct
@@ -129,6 +135,7 @@ func main() {
 
 	if err != nil {
 		fmt.Println("Validation Error:", err)
+		fmt.Println("Bad input, not a bug:", errors.Is(err, errs.InvalidArgument))
 		if user3 != nil {
 			fmt.Println("Error: User 3 should be nil here.")
 		}
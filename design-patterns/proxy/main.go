@@ -1,6 +1,10 @@
 package main
 
-import "fmt"
+import (
+	"fmt"
+
+	"sugar/errs"
+)
 
 // --- 1. The Subject Interface ---
 
@@ -41,9 +45,11 @@ func NewProtectionProxy(service *RealStorageService) *ProtectionProxy {
 // DeleteFile implements the StorageService interface.
 // It includes protection logic before calling the real service.
 func (p *ProtectionProxy) DeleteFile(filename string, userID string) error {
-	// Protection Logic: Only allow deletion if the user is "admin"
+	// Protection Logic: Only allow deletion if the user is "admin".
+	// A PermissionDenied code lets callers branch on the class instead of the text.
 	if userID != "admin" {
-		return fmt.Errorf("[Proxy Check] Access Denied: User %s does not have admin privileges to delete files", userID)
+		return errs.New(errs.PermissionDenied, "[Proxy Check] Access Denied: admin privileges required to delete files",
+			"user", userID, "file", filename)
 	}
 
 	// If access is granted, forward the request to the real service
@@ -59,15 +65,15 @@ func (p *ProtectionProxy) ReadFile(filename string) (string, error) {
 
 func main() {
 	// Create the Real Service instance
-	real := &RealStorageService{}
+	service := &RealStorageService{}
 
 	// Create the Proxy, giving it a reference to the Real Service
-	proxy := NewProtectionProxy(real)
+	proxy := NewProtectionProxy(service)
 
 	fmt.Println("--- User 'guest' attempts to DELETE ---")
 	err := proxy.DeleteFile("secret_data.txt", "guest")
 	if err != nil {
-		fmt.Println("Error:", err)
+		fmt.Printf("Error: %v (%s, HTTP %d)\n", err, errs.CodeOf(err), errs.HTTPStatus(err))
 	}
 
 	fmt.Println("\n--- User 'admin' attempts to DELETE ---")
@@ -0,0 +1,211 @@
+// Package errs classifies errors by what the caller should do about them.
+// A Coded error carries a Code, an optional cause, key/value details and the
+// stack where it was created:
+//
+//	return errs.New(errs.NotFound, "user not found", "id", id)
+//	return errs.Wrap(err, errs.Unavailable, "loading orders", "shard", 3)
+//
+// Callers branch on the class with errors.Is(err, errs.NotFound), or map it
+// straight to a response with errs.HTTPStatus(err).
+package errs
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"runtime"
+	"strings"
+)
+
+// --- 1. The Taxonomy ---
+
+// Code classifies an error by what the caller should do about it,
+// not by where it happened.
+type Code int
+
+const (
+	OK               Code = iota // Not an error
+	NotFound                     // The resource does not exist
+	InvalidArgument              // The request is malformed; retrying won't help
+	PermissionDenied             // The caller may not do this
+	Unavailable                  // Temporary; retrying later may succeed
+	Internal                     // A bug or broken invariant on our side
+	Unknown                      // No code attached; see CodeOf
+)
+
+func (c Code) String() string {
+	switch c {
+	case OK:
+		return "ok"
+	case NotFound:
+		return "not_found"
+	case InvalidArgument:
+		return "invalid_argument"
+	case PermissionDenied:
+		return "permission_denied"
+	case Unavailable:
+		return "unavailable"
+	case Internal:
+		return "internal"
+	case Unknown:
+		return "unknown"
+	}
+	return fmt.Sprintf("Code(%d)", int(c))
+}
+
+// Error lets a Code be used as an errors.Is target:
+// errors.Is(err, NotFound) is true for any Coded error with that code.
+func (c Code) Error() string { return c.String() }
+
+// HTTPStatus maps the code to the status an HTTP handler should answer with.
+func (c Code) HTTPStatus() int {
+	switch c {
+	case OK:
+		return http.StatusOK
+	case NotFound:
+		return http.StatusNotFound
+	case InvalidArgument:
+		return http.StatusBadRequest
+	case PermissionDenied:
+		return http.StatusForbidden
+	case Unavailable:
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusInternalServerError
+}
+
+// --- 2. The Coded Error ---
+
+// Detail is one key/value pair attached to an error.
+type Detail struct {
+	Key   string
+	Value any
+}
+
+// Coded is an error with a Code, an optional cause, details and the stack
+// where it was created.
+type Coded struct {
+	Code    Code
+	Message string
+	Cause   error
+	Details []Detail
+	stack   []uintptr
+}
+
+// New creates a Coded error. kv are alternating keys and values, like slog.
+// Like Wrap it returns an error, not a *Coded: use errors.As to get at the
+// details. A *Coded return would turn into a non-nil error holding a nil
+// pointer the first time Wrap's nil result passed through it.
+func New(code Code, msg string, kv ...any) error {
+	return newCoded(code, msg, nil, kv)
+}
+
+// Wrap creates a Coded error around cause. It returns nil if cause is nil,
+// so `return Wrap(err, ...)` is safe after a call that succeeded.
+func Wrap(cause error, code Code, msg string, kv ...any) error {
+	if cause == nil {
+		return nil
+	}
+	return newCoded(code, msg, cause, kv)
+}
+
+func newCoded(code Code, msg string, cause error, kv []any) *Coded {
+	e := &Coded{Code: code, Message: msg, Cause: cause}
+	for i := 0; i < len(kv); i += 2 {
+		key := fmt.Sprint(kv[i])
+		var value any = "(MISSING)"
+		if i+1 < len(kv) {
+			value = kv[i+1]
+		}
+		e.Details = append(e.Details, Detail{Key: key, Value: value})
+	}
+
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(3, pcs) // Skip runtime.Callers, newCoded and New/Wrap
+	e.stack = pcs[:n]
+	return e
+}
+
+func (e *Coded) Error() string {
+	if e.Cause == nil {
+		return e.Message
+	}
+	return e.Message + ": " + e.Cause.Error()
+}
+
+func (e *Coded) Unwrap() error { return e.Cause }
+
+// Is matches a bare Code, so callers can test the class without errors.As.
+func (e *Coded) Is(target error) bool {
+	code, ok := target.(Code)
+	return ok && e.Code == code
+}
+
+// Detail returns the value stored under key.
+func (e *Coded) Detail(key string) (any, bool) {
+	for _, d := range e.Details {
+		if d.Key == key {
+			return d.Value, true
+		}
+	}
+	return nil, false
+}
+
+// Stack formats the stack captured by New or Wrap.
+func (e *Coded) Stack() string {
+	var sb strings.Builder
+	frames := runtime.CallersFrames(e.stack)
+	for {
+		f, more := frames.Next()
+		fmt.Fprintf(&sb, "%s\n\t%s:%d\n", f.Function, f.File, f.Line)
+		if !more {
+			break
+		}
+	}
+	return sb.String()
+}
+
+// Format prints the message with %v and %s, and adds the code, details and
+// stack with %+v.
+func (e *Coded) Format(s fmt.State, verb rune) {
+	switch {
+	case verb == 'v' && s.Flag('+'):
+		fmt.Fprintf(s, "[%s] %s", e.Code, e.Error())
+		for _, d := range e.Details {
+			fmt.Fprintf(s, " %s=%v", d.Key, d.Value)
+		}
+		fmt.Fprintf(s, "\n%s", e.Stack())
+	case verb == 'q':
+		fmt.Fprintf(s, "%q", e.Error())
+	default:
+		io.WriteString(s, e.Error())
+	}
+}
+
+// --- 3. Reading Codes Back ---
+
+// CodeOf returns the code of the first Coded error in err's tree.
+// Errors that carry no code are Unknown rather than Internal, so CodeOf
+// agrees with errors.Is: errors.Is(err, Internal) is false for them too.
+// HTTPStatus still answers 500 for Unknown, as we don't know what is safe
+// to show.
+func CodeOf(err error) Code {
+	if err == nil {
+		return OK
+	}
+	var coded *Coded
+	if errors.As(err, &coded) {
+		return coded.Code
+	}
+	var code Code
+	if errors.As(err, &code) {
+		return code
+	}
+	return Unknown
+}
+
+// HTTPStatus returns the HTTP status for err.
+func HTTPStatus(err error) int {
+	return CodeOf(err).HTTPStatus()
+}
@@ -0,0 +1,128 @@
+package errs
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestCodedDetailsAndIs(t *testing.T) {
+	err := New(NotFound, "user not found", "id", 42, "table")
+
+	var coded *Coded
+	if !errors.As(err, &coded) {
+		t.Fatalf("errors.As(%v, *Coded) = false", err)
+	}
+	if id, _ := coded.Detail("id"); id != 42 {
+		t.Errorf("Detail(id) = %v, want 42", id)
+	}
+	if table, _ := coded.Detail("table"); table != "(MISSING)" {
+		t.Errorf("Detail(table) = %v, want (MISSING) for a key without value", table)
+	}
+	if !errors.Is(err, NotFound) || errors.Is(err, Internal) {
+		t.Errorf("errors.Is matched the wrong code for %v", err)
+	}
+}
+
+func TestWrapKeepsCauseAndCode(t *testing.T) {
+	dial := errors.New("connection refused")
+	err := fmt.Errorf("GET /orders: %w", Wrap(dial, Unavailable, "loading orders"))
+
+	if !errors.Is(err, dial) {
+		t.Error("cause lost")
+	}
+	if !errors.Is(err, Unavailable) || CodeOf(err) != Unavailable {
+		t.Errorf("code lost: CodeOf = %s", CodeOf(err))
+	}
+	if got, want := err.Error(), "GET /orders: loading orders: connection refused"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
+
+func TestWrapNilIsNil(t *testing.T) {
+	load := func() error { return Wrap(nil, Internal, "unused") }
+	if err := load(); err != nil {
+		t.Fatalf("Wrap(nil) passed through error = %#v, want nil", err)
+	}
+}
+
+// CodeOf and errors.Is must tell the same story about every error.
+func TestCodeOfAgreesWithIs(t *testing.T) {
+	cases := []error{
+		New(NotFound, "no such order"),
+		Wrap(io.ErrUnexpectedEOF, Unavailable, "upstream hung up"),
+		errors.Join(New(InvalidArgument, "bad name"), New(InvalidArgument, "bad email")),
+		PermissionDenied,
+		errors.New("some uncoded error"),
+	}
+	for _, err := range cases {
+		code := CodeOf(err)
+		if code != Unknown && !errors.Is(err, code) {
+			t.Errorf("CodeOf(%v) = %s, but errors.Is(err, %s) = false", err, code, code)
+		}
+		if code != Internal && errors.Is(err, Internal) {
+			t.Errorf("errors.Is(%v, Internal) = true, but CodeOf = %s", err, code)
+		}
+	}
+	if code := CodeOf(errors.New("plain")); code != Unknown {
+		t.Errorf("CodeOf(plain error) = %s, want %s", code, Unknown)
+	}
+}
+
+func TestHTTPStatus(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{nil, http.StatusOK},
+		{New(NotFound, "no such order"), http.StatusNotFound},
+		{New(InvalidArgument, "quantity must be positive"), http.StatusBadRequest},
+		{New(PermissionDenied, "not your order"), http.StatusForbidden},
+		{Wrap(io.ErrUnexpectedEOF, Unavailable, "upstream hung up"), http.StatusServiceUnavailable},
+		{New(Internal, "invariant violated"), http.StatusInternalServerError},
+		{errors.New("some uncoded error"), http.StatusInternalServerError}, // Unknown errors default to 500
+	}
+	for _, tt := range tests {
+		if got := HTTPStatus(tt.err); got != tt.want {
+			t.Errorf("HTTPStatus(%v) = %d, want %d", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestFormatPlusV(t *testing.T) {
+	err := New(NotFound, "user not found", "id", 42)
+
+	if got := fmt.Sprintf("%v", err); got != "user not found" {
+		t.Errorf("%%v = %q", got)
+	}
+	got := fmt.Sprintf("%+v", err)
+	if !strings.HasPrefix(got, "[not_found] user not found id=42\n") {
+		t.Errorf("%%+v = %q, want the code and details first", got)
+	}
+	if !strings.Contains(got, "TestFormatPlusV") {
+		t.Errorf("%%+v stack does not name the caller of New:\n%s", got)
+	}
+}
+
+// A panic with a Coded error can be turned back into a normal error return.
+func TestRecoverCodedPanic(t *testing.T) {
+	load := func() (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				if e, ok := r.(error); ok {
+					err = e
+					return
+				}
+				err = New(Internal, fmt.Sprint(r))
+			}
+		}()
+		panic(New(Internal, "server error"))
+	}
+
+	if err := load(); CodeOf(err) != Internal {
+		t.Fatalf("recovered %v with code %s, want %s", err, CodeOf(err), Internal)
+	}
+}
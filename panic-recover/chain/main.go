@@ -0,0 +1,144 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"time"
+
+	"sugar/panics"
+)
+
+// --- 1. Examples ---
+
+func chainLost() {
+	fmt.Println("\n=== 1. THE PROBLEM: nestedPanicRecover LOSES THE FIRST PANIC ===")
+
+	defer func() {
+		if r := recover(); r != nil {
+			fmt.Printf("Outer recover caught: %v\n", r)
+			fmt.Println("\"Initial panic\" is gone")
+		}
+	}()
+
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				panic("Re-panicking from inner function")
+			}
+		}()
+		panic("Initial panic")
+	}()
+}
+
+func chainKept() {
+	fmt.Println("\n=== 2. THE FIX: Repanic KEEPS THE CHAIN ===")
+
+	err := panics.Catch(panics.RecoverAll, func() {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					panics.Repanic(r, "Re-panicking from inner function")
+				}
+			}()
+			panic("Initial panic")
+		}()
+	})
+
+	fmt.Println("Caught:", err)
+
+	var pe *panics.PanicError
+	errors.As(err, &pe)
+	for i, link := range pe.Chain() {
+		fmt.Printf("  %d. %v\n", i+1, link.Value)
+	}
+
+	// %+v prints every link with its stack; show just the panic lines here
+	for _, line := range strings.Split(fmt.Sprintf("%+v", err), "\n") {
+		if strings.HasPrefix(line, "panic:") || strings.HasPrefix(line, "caused by") {
+			fmt.Println("  " + line)
+		}
+	}
+}
+
+var ErrConfigMissing = errors.New("config file missing")
+
+func chainWithErrors() {
+	fmt.Println("\n=== 3. errors.Is SEES THROUGH THE CHAIN ===")
+
+	err := panics.Catch(panics.RecoverAll, func() {
+		defer func() {
+			if r := recover(); r != nil {
+				panics.Repanic(r, "initialization failed")
+			}
+		}()
+		panic(fmt.Errorf("loading settings: %w", ErrConfigMissing))
+	})
+
+	fmt.Println("Caught:", err)
+	fmt.Println("errors.Is(err, ErrConfigMissing):", errors.Is(err, ErrConfigMissing))
+}
+
+func policyLetsBugsThrough() {
+	fmt.Println("\n=== 4. POLICY: RECOVER INTENTIONAL PANICS, NOT BUGS ===")
+
+	err := panics.Catch(panics.OnlyIntentional, func() {
+		panic("deliberate abort: quota exceeded")
+	})
+	fmt.Println("Deliberate panic recovered:", err)
+
+	// A bug is re-panicked. Here an outer recover stands in for the crash
+	// it would otherwise cause.
+	func() {
+		defer func() {
+			r := recover()
+			_, isRuntime := r.(runtime.Error)
+			fmt.Printf("Bug was NOT recovered by Catch, it escaped: %v (runtime.Error: %t)\n", r, isRuntime)
+		}()
+
+		panics.Catch(panics.OnlyIntentional, func() {
+			var m map[string]int
+			//sugarlint:ignore nilmap a deliberate runtime.Error for the policy to let through
+			m["x"] = 1
+		})
+		fmt.Println("This won't print")
+	}()
+}
+
+func bugOrAbort() {
+	fmt.Println("\n=== 5. TELLING BUGS FROM DELIBERATE ABORTS ===")
+
+	cases := map[string]func(){
+		"nil pointer":  func() { var p *struct{ N int }; _ = p.N },
+		"out of range": func() { s := []int{1, 2, 3}; i := 10; _ = s[i] },
+		"panic(str)":   func() { panic("invalid state transition") },
+		"panic(err)":   func() { panic(ErrConfigMissing) },
+	}
+	for _, name := range []string{"nil pointer", "out of range", "panic(str)", "panic(err)"} {
+		var pe *panics.PanicError
+		errors.As(panics.Catch(panics.RecoverAll, cases[name]), &pe)
+		fmt.Printf("%-13s bug=%-5t %v\n", name, pe.IsBug(), pe)
+	}
+}
+
+func main() {
+	fmt.Println("⛓️ GO PANIC CHAINING AND RECOVER POLICY - COMPLETE GUIDE")
+	fmt.Println("========================================================")
+
+	chainLost()
+	time.Sleep(300 * time.Millisecond)
+
+	chainKept()
+	time.Sleep(300 * time.Millisecond)
+
+	chainWithErrors()
+	time.Sleep(300 * time.Millisecond)
+
+	policyLetsBugsThrough()
+	time.Sleep(300 * time.Millisecond)
+
+	bugOrAbort()
+
+	fmt.Println("\n✅ All examples completed!")
+}
@@ -0,0 +1,19 @@
+package main
+
+import (
+	"testing"
+
+	"sugar/channels/leakcheck"
+)
+
+// Examples run one at a time: leakcheck compares goroutine snapshots, so a
+// parallel test would look like a leak.
+func TestExamplesDoNotLeakOrDeadlock(t *testing.T) {
+	leakcheck.Check(t,
+		leakcheck.Example{Name: "chainLost", Fn: chainLost},
+		leakcheck.Example{Name: "chainKept", Fn: chainKept},
+		leakcheck.Example{Name: "chainWithErrors", Fn: chainWithErrors},
+		leakcheck.Example{Name: "policyLetsBugsThrough", Fn: policyLetsBugsThrough},
+		leakcheck.Example{Name: "bugOrAbort", Fn: bugOrAbort},
+	)
+}
@@ -1,8 +1,14 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"time"
+
+	"sugar/errs"
+	"sugar/panics"
 )
 
 func basicPanic() {
@@ -10,7 +16,7 @@ func basicPanic() {
 
 	fmt.Println("Before panic")
 	panic("Something went wrong!") // Program crashes here
-	fmt.Println("This will NEVER print")
+	// fmt.Println("This will NEVER print") - unreachable, go vet rejects it
 }
 
 func panicWithDifferentTypes() {
@@ -20,13 +26,9 @@ func panicWithDifferentTypes() {
 	// panic("string message")
 	// panic(42)
 	// panic([]string{"error", "list"})
+	// panic(errs.Internal) - an errs.Code is an error on its own
 
-	type CustomError struct {
-		Code    int
-		Message string
-	}
-
-	panic(CustomError{Code: 500, Message: "Server error"})
+	panic(errs.New(errs.Internal, "Server error", "status", 500))
 }
 
 func deferWithPanic() {
@@ -37,36 +39,39 @@ func deferWithPanic() {
 
 	fmt.Println("Before panic")
 	panic("Panic occurred!")
-	fmt.Println("This won't print")
+	// fmt.Println("This won't print") - unreachable, go vet rejects it
 }
 
 func recoverFromPanic() {
 	fmt.Println("\n=== 4. RECOVER FROM PANIC ===")
 
-	// Defer with recover to catch panic
+	// Defer with recover to catch panic. panics.Recovered reports the value
+	// to panics.Default, so it is counted instead of printed and forgotten.
 	defer func() {
 		if r := recover(); r != nil {
-			fmt.Printf("Recovered from panic: %v\n", r)
+			fmt.Printf("Recovered from panic: %v\n", panics.Recovered(r).Value)
 		}
 	}()
 
 	fmt.Println("About to panic...")
 	panic("This panic will be recovered!")
-	fmt.Println("This won't print")
+	// fmt.Println("This won't print") - unreachable, go vet rejects it
 }
 
 func recoverOutsideDefer() {
 	fmt.Println("\n=== 5. RECOVER ONLY WORKS IN DEFER ===")
 
 	// This won't work - recover must be in defer!
+	//sugarlint:ignore recoverdefer shows that recover outside a defer does nothing
 	if r := recover(); r != nil {
+		panics.Recovered(r)
 		fmt.Println("This won't catch anything")
 	}
 
 	// Correct way
 	defer func() {
 		if r := recover(); r != nil {
-			fmt.Printf("Correctly recovered: %v\n", r)
+			fmt.Printf("Correctly recovered: %v\n", panics.Recovered(r).Value)
 		}
 	}()
 
@@ -80,7 +85,7 @@ func multipleDeferWithPanic() {
 	defer fmt.Println("Defer 2 (executes second)")
 	defer func() {
 		if r := recover(); r != nil {
-			fmt.Printf("Defer 1 (executes third): Recovered from: %v\n", r)
+			fmt.Printf("Defer 1 (executes third): Recovered from: %v\n", panics.Recovered(r).Value)
 		}
 	}()
 
@@ -95,7 +100,7 @@ func panicInGoroutine() {
 	go func() {
 		defer func() {
 			if r := recover(); r != nil {
-				fmt.Printf("Goroutine recovered from: %v\n", r)
+				fmt.Printf("Goroutine recovered from: %v\n", panics.Recovered(r).Value)
 			}
 		}()
 
@@ -120,7 +125,7 @@ func panicWithoutRecover() {
 func safeDivision(a, b int) (result int, err error) {
 	defer func() {
 		if r := recover(); r != nil {
-			err = fmt.Errorf("panic occurred: %v", r)
+			err = panics.Recovered(r)
 			result = 0
 		}
 	}()
@@ -156,7 +161,8 @@ func nestedPanicRecover() {
 
 	defer func() {
 		if r := recover(); r != nil {
-			fmt.Printf("Outer recover caught: %v\n", r)
+			// The chain shows the re-panic and the panic it replaced
+			fmt.Printf("Outer recover caught: %v\n", panics.Recovered(r))
 		}
 	}()
 
@@ -164,8 +170,8 @@ func nestedPanicRecover() {
 		defer func() {
 			if r := recover(); r != nil {
 				fmt.Printf("Inner recover caught: %v\n", r)
-				// Can panic again!
-				panic("Re-panicking from inner function")
+				// Can panic again! Repanic keeps the first panic as the cause
+				panics.Repanic(r, "Re-panicking from inner function")
 			}
 		}()
 
@@ -178,7 +184,7 @@ func outOfBoundsPanic() {
 
 	defer func() {
 		if r := recover(); r != nil {
-			fmt.Printf("Recovered from out of bounds: %v\n", r)
+			fmt.Printf("Recovered from out of bounds: %v\n", panics.Recovered(r).Value)
 		}
 	}()
 
@@ -195,7 +201,7 @@ func nilPointerPanic() {
 
 	defer func() {
 		if r := recover(); r != nil {
-			fmt.Printf("Recovered from nil pointer: %v\n", r)
+			fmt.Printf("Recovered from nil pointer: %v\n", panics.Recovered(r).Value)
 		}
 	}()
 
@@ -230,6 +236,78 @@ func panicVsError() {
 	// Bad: panic("file not found")
 }
 
+// --- Panic Telemetry ---
+
+func panicTelemetry() {
+	fmt.Println("\n=== 14. PANIC TELEMETRY - ONE GROUP PER BUG ===")
+
+	// Every recover above reported to panics.Default
+	snap := panics.Default.Snapshot()
+	fmt.Printf("Examples so far recovered %d panics into %d group(s):\n", snap.Total, len(snap.Entries))
+	for _, e := range snap.Entries {
+		fmt.Printf("%3d x %-22s %s\n", e.Count, e.SampleType, e.Sample)
+	}
+
+	// The same bug hit from the same place again and again is one group
+	// with a count, not a new line in the log every time
+	panics.Default.Reset()
+	for i := 0; i < 3; i++ {
+		nilPointerPanic()
+	}
+
+	snap = panics.Default.Snapshot()
+	e := snap.Entries[0]
+	fmt.Printf("\nnilPointerPanic 3 times: %d group(s), count %d, last seen %s\n",
+		len(snap.Entries), e.Count, e.LastSeen.Format("15:04:05.000"))
+}
+
+func boundedTelemetry() {
+	fmt.Println("\n=== 15. BOUNDED NUMBER OF GROUPS ===")
+
+	// A registry of its own, capped at 2 groups
+	reg := panics.NewRegistry(2)
+	sites := []func(){
+		func() { panic("a") },
+		func() { panic("b") },
+		func() { panic("c") },
+	}
+	for _, site := range sites {
+		func() {
+			defer reg.Capture()
+			site()
+		}()
+	}
+
+	snap := reg.Snapshot()
+	fmt.Printf("Groups kept: %d, panics dropped: %d\n", len(snap.Entries), snap.Dropped)
+}
+
+func telemetryEndpoint() {
+	fmt.Println("\n=== 16. HTTP DEBUG ENDPOINT ===")
+
+	mux := http.NewServeMux()
+	mux.Handle("/debug/panics", panics.Default.Handler())
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/debug/panics")
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer resp.Body.Close()
+
+	var snap panics.Snapshot
+	json.NewDecoder(resp.Body).Decode(&snap)
+	fmt.Printf("GET /debug/panics -> %s, %d group(s), total %d\n", resp.Status, len(snap.Entries), snap.Total)
+	if len(snap.Entries) > 0 {
+		fmt.Println("Most frequent, where it panicked:")
+		for _, f := range snap.Entries[0].Stack {
+			fmt.Printf("  %s:%d\n", f.Function, f.Line)
+		}
+	}
+}
+
 func main() {
 	fmt.Println("💥 GO PANIC AND RECOVER - COMPLETE GUIDE")
 	fmt.Println("==========================================")
@@ -241,7 +319,7 @@ func main() {
 	func() {
 		defer func() {
 			if r := recover(); r != nil {
-				fmt.Printf("\nCaught panic from basicPanic: %v\n", r)
+				fmt.Printf("\nCaught panic from basicPanic: %v\n", panics.Recovered(r).Value)
 			}
 		}()
 		basicPanic()
@@ -251,9 +329,7 @@ func main() {
 
 	// Example 3: Defer with panic
 	func() {
-		defer func() {
-			recover() // Catch to continue
-		}()
+		defer panics.Default.Capture() // Catch to continue
 		deferWithPanic()
 	}()
 
@@ -287,6 +363,15 @@ func main() {
 	time.Sleep(300 * time.Millisecond)
 
 	panicVsError()
+	time.Sleep(300 * time.Millisecond)
+
+	panicTelemetry()
+	time.Sleep(300 * time.Millisecond)
+
+	boundedTelemetry()
+	time.Sleep(300 * time.Millisecond)
+
+	telemetryEndpoint()
 
 	fmt.Println("\n✅ All examples completed without crashing!")
 }
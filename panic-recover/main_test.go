@@ -0,0 +1,38 @@
+package main
+
+import (
+	"testing"
+
+	"sugar/channels/leakcheck"
+)
+
+// Examples run one at a time: leakcheck compares goroutine snapshots, so a
+// parallel test would look like a leak.
+func TestExamplesDoNotLeakOrDeadlock(t *testing.T) {
+	leakcheck.Check(t,
+		leakcheck.Example{Name: "basicPanic", Fn: recovering(basicPanic)},
+		leakcheck.Example{Name: "panicWithDifferentTypes", Fn: recovering(panicWithDifferentTypes)},
+		leakcheck.Example{Name: "deferWithPanic", Fn: recovering(deferWithPanic)},
+		leakcheck.Example{Name: "recoverFromPanic", Fn: recoverFromPanic},
+		leakcheck.Example{Name: "recoverOutsideDefer", Fn: recoverOutsideDefer},
+		leakcheck.Example{Name: "multipleDeferWithPanic", Fn: multipleDeferWithPanic},
+		leakcheck.Example{Name: "panicInGoroutine", Fn: panicInGoroutine},
+		leakcheck.Example{Name: "panicWithoutRecover", Fn: panicWithoutRecover},
+		leakcheck.Example{Name: "divisionExample", Fn: divisionExample},
+		leakcheck.Example{Name: "nestedPanicRecover", Fn: nestedPanicRecover},
+		leakcheck.Example{Name: "outOfBoundsPanic", Fn: outOfBoundsPanic},
+		leakcheck.Example{Name: "nilPointerPanic", Fn: nilPointerPanic},
+		leakcheck.Example{Name: "panicVsError", Fn: panicVsError},
+		leakcheck.Example{Name: "panicTelemetry", Fn: panicTelemetry},
+		leakcheck.Example{Name: "boundedTelemetry", Fn: boundedTelemetry},
+		leakcheck.Example{Name: "telemetryEndpoint", Fn: telemetryEndpoint},
+	)
+}
+
+// recovering runs an example that panics on purpose, the way main wraps it.
+func recovering(fn func()) func() {
+	return func() {
+		defer func() { recover() }()
+		fn()
+	}
+}
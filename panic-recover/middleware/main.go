@@ -0,0 +1,284 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"sugar/panics"
+)
+
+// --- 1. Request IDs ---
+
+// RequestIDHeader is read from incoming requests and echoed on responses.
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLen bounds a client-supplied ID. It ends up in logs and
+// response headers, so anything longer or odder is replaced.
+const maxRequestIDLen = 64
+
+type requestIDKey struct{}
+
+// RequestID returns the ID the middleware stored in ctx.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+func newRequestID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// requestID returns the client's ID if it is safe to log and echo, or a new one.
+func requestID(r *http.Request) string {
+	id := r.Header.Get(RequestIDHeader)
+	if !validRequestID(id) {
+		return newRequestID()
+	}
+	return id
+}
+
+// validRequestID allows 1 to maxRequestIDLen letters, digits, '-', '_' and
+// '.' - enough for UUIDs and trace IDs, and nothing that can forge a log
+// line or smuggle a header.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLen {
+		return false
+	}
+	for _, c := range []byte(id) {
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+		case c == '-', c == '_', c == '.':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// --- 2. Tracking Whether the Response Started ---
+
+// trackingWriter remembers whether the status line has been sent. Once it
+// has, a 500 can no longer be written.
+type trackingWriter struct {
+	http.ResponseWriter
+	started bool
+}
+
+func (w *trackingWriter) WriteHeader(status int) {
+	if status >= 200 { // 1xx informational headers don't commit the response
+		w.started = true
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *trackingWriter) Write(p []byte) (int, error) {
+	w.started = true
+	return w.ResponseWriter.Write(p)
+}
+
+// Flush keeps streaming handlers working: they type-assert http.Flusher,
+// which the embedded interface does not expose.
+func (w *trackingWriter) Flush() {
+	w.started = true // Flushing sends the status line
+	http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Hijack passes http.Hijacker through. Once the connection is taken over no
+// 500 can be written, so it counts as a started response.
+func (w *trackingWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := http.NewResponseController(w.ResponseWriter).Hijack()
+	if err == nil {
+		w.started = true
+	}
+	return conn, rw, err
+}
+
+// Unwrap lets http.ResponseController reach deadlines and anything else.
+func (w *trackingWriter) Unwrap() http.ResponseWriter { return w.ResponseWriter }
+
+// --- 3. The Middleware ---
+
+// errorBody is the JSON returned with a 500.
+type errorBody struct {
+	Error     string `json:"error"`
+	RequestID string `json:"request_id"`
+}
+
+// Recover turns a panic in next into a logged JSON 500.
+//
+// http.ErrAbortHandler is re-panicked untouched: it is how a handler asks the
+// server to drop the connection. If the response had already started, the
+// status can't be changed, so the panic is logged and the connection is
+// aborted instead - the client sees a broken response, not a truncated 200.
+func Recover(logger *slog.Logger, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := requestID(r)
+		w.Header().Set(RequestIDHeader, id)
+		r = r.WithContext(context.WithValue(r.Context(), requestIDKey{}, id))
+
+		tw := &trackingWriter{ResponseWriter: w}
+
+		defer func() {
+			rec := recover()
+			if rec == nil {
+				return
+			}
+			if rec == http.ErrAbortHandler {
+				panic(rec)
+			}
+			pe := panics.Recovered(rec) // Counted even when nobody reads the logs
+
+			logger.ErrorContext(r.Context(), "panic in HTTP handler",
+				"request_id", id,
+				"method", r.Method,
+				"path", r.URL.Path,
+				"panic", fmt.Sprint(pe.Value),
+				"response_started", tw.started,
+				"stack", string(pe.Stack),
+			)
+
+			if tw.started {
+				panic(http.ErrAbortHandler)
+			}
+
+			// Start from clean headers: whatever the handler set was meant for
+			// its own body (Content-Length, Set-Cookie, caching...), not this one
+			h := w.Header()
+			clear(h)
+			h.Set(RequestIDHeader, id)
+			h.Set("Content-Type", "application/json")
+			h.Set("X-Content-Type-Options", "nosniff")
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(errorBody{Error: "internal server error", RequestID: id})
+		}()
+
+		next.ServeHTTP(tw, r)
+	})
+}
+
+// --- 4. Examples ---
+
+// newLogger returns a JSON slog logger writing into buf, so the examples
+// (and main_test.go) can show what was logged.
+func newLogger(buf *bytes.Buffer) *slog.Logger {
+	return slog.New(slog.NewJSONHandler(buf, nil))
+}
+
+// serve runs h for one request and reports a panic that escaped it.
+func serve(h http.Handler, req *http.Request) (rec *httptest.ResponseRecorder, escaped any) {
+	rec = httptest.NewRecorder()
+	defer func() { escaped = recover() }()
+	h.ServeHTTP(rec, req)
+	return rec, nil
+}
+
+func passThrough() {
+	fmt.Println("\n=== 1. HEALTHY HANDLER PASSES THROUGH ===")
+
+	var logs bytes.Buffer
+	h := Recover(newLogger(&logs), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "hello, request %s", RequestID(r.Context()))
+	}))
+
+	// A client's ID is kept only if it is safe to log and echo back
+	for _, c := range []struct{ name, id string }{
+		{"valid", "abc123"},
+		{"newline", "forged\nlog line"},
+		{"100 bytes", strings.Repeat("x", 100)},
+	} {
+		req := httptest.NewRequest("GET", "/hello", nil)
+		req.Header.Set(RequestIDHeader, c.id)
+		rec, _ := serve(h, req)
+		fmt.Printf("%-9s ID -> %d %q\n", c.name, rec.Code, rec.Body.String())
+	}
+	fmt.Println("Logged:", logs.Len(), "bytes")
+}
+
+func panicBecomes500() {
+	fmt.Println("\n=== 2. PANIC BECOMES A JSON 500 ===")
+
+	var logs bytes.Buffer
+	h := Recover(newLogger(&logs), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain") // Must not leak into the 500
+		w.Header().Set("Set-Cookie", "session=half-made")
+		var m map[string]int
+		//sugarlint:ignore nilmap a deliberate runtime panic for the middleware to recover
+		m["boom"]++ // nil map write: runtime panic
+	}))
+
+	rec, _ := serve(h, httptest.NewRequest("POST", "/orders", nil))
+	fmt.Printf("Status: %d, Content-Type: %s, Set-Cookie: %q\n",
+		rec.Code, rec.Header().Get("Content-Type"), rec.Header().Get("Set-Cookie"))
+	fmt.Print("Body: ", rec.Body.String())
+
+	var entry map[string]any
+	json.Unmarshal(logs.Bytes(), &entry)
+	fmt.Printf("Logged: %v (request_id=%v)\n", entry["panic"], entry["request_id"])
+}
+
+func abortHandlerRepanics() {
+	fmt.Println("\n=== 3. http.ErrAbortHandler IS RE-PANICKED ===")
+
+	var logs bytes.Buffer
+	h := Recover(newLogger(&logs), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	}))
+
+	_, escaped := serve(h, httptest.NewRequest("GET", "/stream", nil))
+	fmt.Printf("Reached the server: %v, logged: %d bytes\n", escaped, logs.Len())
+}
+
+func panicAfterResponseStarted() {
+	fmt.Println("\n=== 4. PANIC AFTER THE RESPONSE STARTED ===")
+
+	var logs bytes.Buffer
+	h := Recover(newLogger(&logs), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		io.WriteString(w, `{"items": [`)
+		w.(http.Flusher).Flush() // Still a Flusher behind the middleware
+		panic("lost database connection mid-stream")
+	}))
+
+	// Against a real server the client sees the abort as a failed read
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	resp, err := srv.Client().Get(srv.URL + "/items")
+	if err == nil {
+		_, err = io.ReadAll(resp.Body)
+		resp.Body.Close()
+	}
+	fmt.Println("Client read:", err)
+}
+
+func main() {
+	fmt.Println("🛟 GO PANIC RECOVERY MIDDLEWARE - COMPLETE GUIDE")
+	fmt.Println("================================================")
+
+	passThrough()
+	time.Sleep(300 * time.Millisecond)
+
+	panicBecomes500()
+	time.Sleep(300 * time.Millisecond)
+
+	abortHandlerRepanics()
+	time.Sleep(300 * time.Millisecond)
+
+	panicAfterResponseStarted()
+
+	fmt.Println("\n✅ All examples completed!")
+}
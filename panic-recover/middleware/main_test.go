@@ -0,0 +1,240 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"sugar/panics"
+
+	"sugar/channels/leakcheck"
+)
+
+func TestPassThrough(t *testing.T) {
+	var logs bytes.Buffer
+	h := Recover(newLogger(&logs), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "hello, request %s", RequestID(r.Context()))
+	}))
+
+	req := httptest.NewRequest("GET", "/hello", nil)
+	req.Header.Set(RequestIDHeader, "abc123")
+	rec, _ := serve(h, req)
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("status = %d, want 200", rec.Code)
+	}
+	if got := rec.Body.String(); got != "hello, request abc123" {
+		t.Errorf("body = %q, want the incoming request ID", got)
+	}
+	if got := rec.Header().Get(RequestIDHeader); got != "abc123" {
+		t.Errorf("%s = %q, want it echoed", RequestIDHeader, got)
+	}
+	if logs.Len() != 0 {
+		t.Errorf("logged %q, want nothing", logs.String())
+	}
+}
+
+func TestUnsafeRequestIDIsReplaced(t *testing.T) {
+	h := Recover(newLogger(new(bytes.Buffer)), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	tests := []struct {
+		id   string
+		keep bool
+	}{
+		{"abc123", true},
+		{"3f2b9c1e-7d4a-4b8e-9f6a-0c5d2e1b7a94", true},
+		{"trace_01.span-02", true},
+		{strings.Repeat("x", maxRequestIDLen), true},
+		{strings.Repeat("x", maxRequestIDLen+1), false},
+		{"forged\nlevel=ERROR msg=pwned", false},
+		{"<script>", false},
+		{"id with spaces", false},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header[http.CanonicalHeaderKey(RequestIDHeader)] = []string{tt.id} // Raw, so the newline survives
+		rec, _ := serve(h, req)
+
+		got := rec.Header().Get(RequestIDHeader)
+		if kept := got == tt.id; kept != tt.keep {
+			t.Errorf("ID %q: echoed %q, want kept=%v", tt.id, got, tt.keep)
+		}
+		if !validRequestID(got) {
+			t.Errorf("ID %q: echoed unsafe replacement %q", tt.id, got)
+		}
+	}
+}
+
+func TestPanicBecomes500(t *testing.T) {
+	before := panics.Default.Snapshot().Total
+	var logs bytes.Buffer
+	h := Recover(newLogger(&logs), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.Header().Set("Content-Length", "42")
+		w.Header().Set("Set-Cookie", "session=half-made")
+		w.Header().Set("Cache-Control", "max-age=3600")
+		var m map[string]int
+		//sugarlint:ignore nilmap a deliberate runtime panic for the middleware to recover
+		m["boom"]++
+	}))
+
+	rec, escaped := serve(h, httptest.NewRequest("POST", "/orders", nil))
+
+	if escaped != nil {
+		t.Fatalf("panic escaped the middleware: %v", escaped)
+	}
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want 500", rec.Code)
+	}
+	if got := rec.Header().Get("Content-Type"); got != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", got)
+	}
+	for _, leaked := range []string{"Content-Length", "Set-Cookie", "Cache-Control"} {
+		if v := rec.Header().Get(leaked); v != "" {
+			t.Errorf("handler's %s = %q leaked into the 500", leaked, v)
+		}
+	}
+
+	var body errorBody
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("body %q is not JSON: %v", rec.Body.String(), err)
+	}
+	id := rec.Header().Get(RequestIDHeader)
+	if id == "" || body.RequestID != id {
+		t.Errorf("body request_id = %q, header = %q, want the same generated ID", body.RequestID, id)
+	}
+
+	var entry map[string]any
+	json.Unmarshal(logs.Bytes(), &entry)
+	if entry["request_id"] != id {
+		t.Errorf("log request_id = %v, want %s", entry["request_id"], id)
+	}
+	if !strings.Contains(fmt.Sprint(entry["panic"]), "nil map") {
+		t.Errorf("log panic = %v, want the panic value", entry["panic"])
+	}
+	if !strings.Contains(fmt.Sprint(entry["stack"]), "TestPanicBecomes500") {
+		t.Error("log stack does not include the panicking handler")
+	}
+	if got := panics.Default.Snapshot().Total; got != before+1 {
+		t.Errorf("panics.Default total = %d, want %d", got, before+1)
+	}
+}
+
+func TestAbortHandlerRepanics(t *testing.T) {
+	var logs bytes.Buffer
+	h := Recover(newLogger(&logs), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	}))
+
+	rec, escaped := serve(h, httptest.NewRequest("GET", "/stream", nil))
+
+	if escaped != http.ErrAbortHandler {
+		t.Errorf("escaped = %v, want http.ErrAbortHandler", escaped)
+	}
+	if logs.Len() != 0 {
+		t.Errorf("deliberate abort was logged: %s", logs.String())
+	}
+	if rec.Flushed || rec.Body.Len() != 0 {
+		t.Error("a 500 body was written")
+	}
+}
+
+func TestPanicAfterResponseStarted(t *testing.T) {
+	var logs bytes.Buffer
+	h := Recover(newLogger(&logs), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		io.WriteString(w, `{"items": [`)
+		panic("lost database connection mid-stream")
+	}))
+
+	rec, escaped := serve(h, httptest.NewRequest("GET", "/items", nil))
+
+	if escaped != http.ErrAbortHandler {
+		t.Errorf("escaped = %v, want the connection aborted", escaped)
+	}
+	if rec.Code != http.StatusOK {
+		t.Errorf("status = %d, want the 200 left alone", rec.Code)
+	}
+	if strings.Contains(rec.Body.String(), "internal server error") {
+		t.Error("JSON error appended to the partial body")
+	}
+	var entry map[string]any
+	json.Unmarshal(logs.Bytes(), &entry)
+	if entry["response_started"] != true {
+		t.Errorf("log response_started = %v, want true", entry["response_started"])
+	}
+
+	// Against a real server the client sees the abort as a failed read
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	resp, err := srv.Client().Get(srv.URL + "/items")
+	if err == nil {
+		_, err = io.ReadAll(resp.Body)
+		resp.Body.Close()
+	}
+	if err == nil {
+		t.Error("client read a silently truncated 200")
+	}
+}
+
+func TestFlusherAndHijackerPassThrough(t *testing.T) {
+	var logs bytes.Buffer
+	h := Recover(newLogger(&logs), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/flush":
+			f, ok := w.(http.Flusher)
+			if !ok {
+				http.Error(w, "no Flusher", http.StatusInternalServerError)
+				return
+			}
+			io.WriteString(w, "chunk")
+			f.Flush()
+		case "/hijack":
+			hj, ok := w.(http.Hijacker)
+			if !ok {
+				http.Error(w, "no Hijacker", http.StatusInternalServerError)
+				return
+			}
+			conn, rw, err := hj.Hijack()
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			defer conn.Close()
+			rw.WriteString("HTTP/1.1 200 OK\r\nContent-Length: 8\r\nConnection: close\r\n\r\nhijacked")
+			rw.Flush()
+		}
+	}))
+
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	for path, want := range map[string]string{"/flush": "chunk", "/hijack": "hijacked"} {
+		resp, err := srv.Client().Get(srv.URL + path)
+		if err != nil {
+			t.Fatalf("GET %s: %v", path, err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK || string(body) != want {
+			t.Errorf("GET %s = %d %q, want 200 %q", path, resp.StatusCode, body, want)
+		}
+	}
+}
+
+// Examples run one at a time: leakcheck compares goroutine snapshots, so a
+// parallel test would look like a leak.
+func TestExamplesDoNotLeakOrDeadlock(t *testing.T) {
+	leakcheck.Check(t,
+		leakcheck.Example{Name: "passThrough", Fn: passThrough},
+		leakcheck.Example{Name: "panicBecomes500", Fn: panicBecomes500},
+		leakcheck.Example{Name: "abortHandlerRepanics", Fn: abortHandlerRepanics},
+		leakcheck.Example{Name: "panicAfterResponseStarted", Fn: panicAfterResponseStarted},
+	)
+}
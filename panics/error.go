@@ -0,0 +1,141 @@
+package panics
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"runtime/debug"
+)
+
+// --- 4. PanicError: A Recovered Panic With Its Cause ---
+
+// PanicError is a recovered panic turned into an error. When a recover
+// handler panics again, Cause holds the panic it was handling, so the
+// original failure is not lost.
+type PanicError struct {
+	Value any    // What was passed to panic
+	Stack []byte // Stack at the point of recovery
+	Cause error  // The earlier panic (or error) this one replaced, if any
+}
+
+// newPanicError must be called from the deferred function that recovered,
+// so the stack still shows where the panic happened.
+func newPanicError(value any, cause error) *PanicError {
+	return &PanicError{Value: value, Stack: debug.Stack(), Cause: cause}
+}
+
+// asPanicError turns a recovered value into a *PanicError, keeping an
+// existing one (and so its chain) as it is.
+func asPanicError(recovered any) *PanicError {
+	if pe, ok := recovered.(*PanicError); ok {
+		return pe
+	}
+	return newPanicError(recovered, nil)
+}
+
+func (e *PanicError) Error() string {
+	msg := fmt.Sprintf("panic: %v", e.Value)
+	if e.Cause != nil {
+		msg += " (while handling " + e.Cause.Error() + ")"
+	}
+	return msg
+}
+
+// Unwrap exposes the cause, and an error passed to panic, to errors.Is/As.
+func (e *PanicError) Unwrap() []error {
+	var errs []error
+	if err, ok := e.Value.(error); ok {
+		errs = append(errs, err)
+	}
+	if e.Cause != nil {
+		errs = append(errs, e.Cause)
+	}
+	return errs
+}
+
+// IsBug reports whether any panic in the chain came from the runtime - nil
+// dereference, index out of range, and so on - rather than a deliberate
+// panic call. Re-panicking on top of a bug doesn't make it intentional.
+func (e *PanicError) IsBug() bool {
+	for _, link := range e.Chain() {
+		if _, ok := link.Value.(runtime.Error); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Chain lists this panic and every panic it replaced, newest first.
+func (e *PanicError) Chain() []*PanicError {
+	var chain []*PanicError
+	for cur := e; cur != nil; {
+		chain = append(chain, cur)
+		next, ok := cur.Cause.(*PanicError)
+		if !ok {
+			break
+		}
+		cur = next
+	}
+	return chain
+}
+
+// Format prints the one-line chain with %v, and every link with its stack
+// with %+v.
+func (e *PanicError) Format(s fmt.State, verb rune) {
+	if verb != 'v' || !s.Flag('+') {
+		io.WriteString(s, e.Error())
+		return
+	}
+	for i, link := range e.Chain() {
+		if i > 0 {
+			io.WriteString(s, "\ncaused by ")
+		}
+		fmt.Fprintf(s, "panic: %v\n%s", link.Value, link.Stack)
+	}
+}
+
+// --- 5. Recover Policy ---
+
+// Policy decides which panics a helper may recover. A panic it rejects is
+// re-panicked unchanged, so the program crashes with the original stack.
+type Policy func(value any) bool
+
+// RecoverAll recovers every panic.
+func RecoverAll(any) bool { return true }
+
+// OnlyIntentional recovers deliberate panic calls but lets runtime.Error
+// through: a nil dereference is a bug, and carrying on after it may leave
+// state half-updated.
+func OnlyIntentional(value any) bool {
+	if pe, ok := value.(*PanicError); ok {
+		return !pe.IsBug()
+	}
+	_, bug := value.(runtime.Error)
+	return !bug
+}
+
+// --- 6. Recover Helpers ---
+
+// Catch runs fn and returns a recovered panic as a *PanicError, reported
+// to Default. A panic the policy rejects is neither reported nor recovered.
+func Catch(policy Policy, fn func()) (err error) {
+	defer func() {
+		r := recover()
+		if r == nil {
+			return
+		}
+		if !policy(r) {
+			panic(r)
+		}
+		Default.report(r, 2)
+		err = asPanicError(r)
+	}()
+	fn()
+	return nil
+}
+
+// Repanic panics with value, keeping recovered as its cause. Use it in a
+// recover handler that has to panic again.
+func Repanic(recovered, value any) {
+	panic(newPanicError(value, asPanicError(recovered)))
+}
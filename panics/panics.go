@@ -0,0 +1,253 @@
+// Package panics collects recovered panics instead of letting each recover
+// site print the value and forget it. Every recover helper in this repo
+// turns the value into a *PanicError and reports it to a Registry, which
+// groups panics by a stack fingerprint:
+//
+//	defer func() {
+//		if r := recover(); r != nil {
+//			err = panics.Recovered(r)
+//		}
+//	}()
+//
+// The registry keeps a count, first and last seen times and a sample per
+// group, and serves a snapshot over HTTP with Handler.
+package panics
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"net/http"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// --- 1. Fingerprinting a Panic ---
+
+// maxFrames is how many frames below the panic site go into a fingerprint.
+// Deeper frames mostly tell us who called the caller, and would split one
+// bug into many groups.
+const maxFrames = 8
+
+// Frame is one call site in a recovered panic's stack.
+type Frame struct {
+	Function string `json:"function"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+}
+
+func (f Frame) String() string {
+	return fmt.Sprintf("%s (%s:%d)", f.Function, f.File, f.Line)
+}
+
+// panicFrames returns the frames of the code that panicked. Called from a
+// deferred recover, the stack still holds the panicking frames below
+// runtime.gopanic; everything above it is the recover machinery.
+func panicFrames(skip int) []Frame {
+	pcs := make([]uintptr, 64)
+	n := runtime.Callers(skip+1, pcs)
+	iter := runtime.CallersFrames(pcs[:n])
+
+	var all []Frame
+	panicAt := -1
+	for {
+		f, more := iter.Next()
+		if f.Function == "runtime.gopanic" {
+			panicAt = len(all)
+		}
+		all = append(all, Frame{Function: f.Function, File: f.File, Line: f.Line})
+		if !more {
+			break
+		}
+	}
+
+	frames := all[panicAt+1:] // Not in a panic: panicAt is -1 and we keep everything
+	// Runtime errors pass through helpers like runtime.panicmem or
+	// runtime.goPanicIndex before gopanic; they say nothing about our code.
+	for len(frames) > 0 && strings.HasPrefix(frames[0].Function, "runtime.") {
+		frames = frames[1:]
+	}
+	if len(frames) > maxFrames {
+		frames = frames[:maxFrames]
+	}
+	return frames
+}
+
+// fingerprint hashes function names only. Line numbers and paths are left
+// out on purpose, so a redeploy that shifts code around keeps the same groups.
+func fingerprint(frames []Frame) string {
+	h := fnv.New64a()
+	for _, f := range frames {
+		io.WriteString(h, f.Function)
+		h.Write([]byte{0})
+	}
+	return fmt.Sprintf("%016x", h.Sum64())
+}
+
+// --- 2. The Registry ---
+
+// Entry aggregates every panic that shares a fingerprint.
+type Entry struct {
+	Fingerprint string    `json:"fingerprint"`
+	Site        string    `json:"site"` // Where it panicked
+	Count       int       `json:"count"`
+	FirstSeen   time.Time `json:"first_seen"`
+	LastSeen    time.Time `json:"last_seen"`
+	Sample      string    `json:"sample"` // The first recovered value
+	SampleType  string    `json:"sample_type"`
+	Stack       []Frame   `json:"stack"`
+}
+
+// Registry collects recovered panics so they are counted instead of
+// printed and forgotten.
+type Registry struct {
+	mu         sync.Mutex
+	entries    map[string]*Entry
+	maxEntries int
+	dropped    int // Panics not stored because maxEntries was reached
+
+	now func() time.Time
+}
+
+// NewRegistry creates a registry that keeps at most maxEntries distinct
+// fingerprints, so a panic with an ever-changing stack can't grow it forever.
+func NewRegistry(maxEntries int) *Registry {
+	return &Registry{
+		entries:    make(map[string]*Entry),
+		maxEntries: maxEntries,
+		now:        time.Now,
+	}
+}
+
+// Default is the process-wide registry the package-level helpers report to.
+var Default = NewRegistry(1000)
+
+// Report records a recovered value. Call it from the deferred function that
+// called recover, so the panicking frames are still on the stack.
+func (r *Registry) Report(value any) {
+	r.report(value, 2)
+}
+
+// report skips its own frame and `skip` callers when capturing the stack.
+func (r *Registry) report(value any, skip int) {
+	frames := panicFrames(skip + 1)
+	fp := fingerprint(frames)
+	now := r.now()
+
+	sample := value
+	if pe, ok := value.(*PanicError); ok {
+		sample = pe.Value // Group under what was actually panicked with
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	e, ok := r.entries[fp]
+	if !ok {
+		if len(r.entries) >= r.maxEntries {
+			r.dropped++
+			return
+		}
+		e = &Entry{
+			Fingerprint: fp,
+			FirstSeen:   now,
+			Sample:      fmt.Sprint(sample),
+			SampleType:  fmt.Sprintf("%T", sample),
+			Stack:       frames,
+		}
+		if len(frames) > 0 {
+			e.Site = frames[0].String()
+		}
+		r.entries[fp] = e
+	}
+	e.Count++
+	e.LastSeen = now
+}
+
+// Capture recovers a panic and reports it. Use it directly as
+// `defer reg.Capture()`; recover only works when called by the deferred
+// function itself, so it can't be wrapped in another closure.
+func (r *Registry) Capture() {
+	if v := recover(); v != nil {
+		r.report(v, 2)
+	}
+}
+
+// Recovered turns a value returned by recover into a *PanicError and
+// reports it. Call it from the deferred function that recovered.
+func (r *Registry) Recovered(value any) *PanicError {
+	r.report(value, 2)
+	return asPanicError(value)
+}
+
+// Recovered is Default.Recovered.
+func Recovered(value any) *PanicError {
+	Default.report(value, 2)
+	return asPanicError(value)
+}
+
+// Safe runs fn and turns a panic into a *PanicError.
+func (r *Registry) Safe(fn func()) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			r.report(v, 2)
+			err = asPanicError(v)
+		}
+	}()
+	fn()
+	return nil
+}
+
+// Snapshot is a point-in-time copy of the registry.
+type Snapshot struct {
+	TakenAt time.Time `json:"taken_at"`
+	Total   int       `json:"total"`
+	Dropped int       `json:"dropped"`
+	Entries []Entry   `json:"entries"` // Most frequent first
+}
+
+// Snapshot copies the current entries. The copy is safe to keep and modify.
+func (r *Registry) Snapshot() Snapshot {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s := Snapshot{TakenAt: r.now(), Dropped: r.dropped}
+	for _, e := range r.entries {
+		c := *e
+		c.Stack = append([]Frame(nil), e.Stack...)
+		s.Entries = append(s.Entries, c)
+		s.Total += e.Count
+	}
+	sort.Slice(s.Entries, func(i, j int) bool {
+		if s.Entries[i].Count != s.Entries[j].Count {
+			return s.Entries[i].Count > s.Entries[j].Count
+		}
+		return s.Entries[i].FirstSeen.Before(s.Entries[j].FirstSeen)
+	})
+	return s
+}
+
+// Reset forgets everything, e.g. after the snapshot was shipped elsewhere.
+func (r *Registry) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = make(map[string]*Entry)
+	r.dropped = 0
+}
+
+// --- 3. HTTP Debug Endpoint ---
+
+// Handler serves the snapshot as JSON. Mount it next to pprof,
+// e.g. mux.Handle("/debug/panics", reg.Handler()).
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		enc.Encode(r.Snapshot())
+	})
+}
@@ -0,0 +1,154 @@
+package panics
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func nilDeref() {
+	var p *struct{ N int }
+	_ = p.N
+}
+
+func TestSameSiteGroupsTogether(t *testing.T) {
+	reg := NewRegistry(10)
+	for range 100 {
+		func() {
+			defer reg.Capture()
+			nilDeref()
+		}()
+	}
+	reg.Safe(func() { panic("elsewhere") })
+
+	snap := reg.Snapshot()
+	if snap.Total != 101 || len(snap.Entries) != 2 {
+		t.Fatalf("Snapshot() total %d in %d groups, want 101 in 2", snap.Total, len(snap.Entries))
+	}
+	e := snap.Entries[0]
+	if e.Count != 100 || !strings.Contains(e.Site, "nilDeref") {
+		t.Errorf("top group = %d x %s, want 100 x nilDeref", e.Count, e.Site)
+	}
+	if e.SampleType != "runtime.errorString" {
+		t.Errorf("SampleType = %s, want runtime.errorString", e.SampleType)
+	}
+}
+
+func TestMaxEntriesDropsNewGroups(t *testing.T) {
+	reg := NewRegistry(1)
+	for _, site := range []func(){
+		func() { panic("a") },
+		func() { panic("b") },
+	} {
+		func() {
+			defer reg.Capture()
+			site()
+		}()
+	}
+
+	if snap := reg.Snapshot(); len(snap.Entries) != 1 || snap.Dropped != 1 {
+		t.Fatalf("kept %d groups, dropped %d, want 1 and 1", len(snap.Entries), snap.Dropped)
+	}
+}
+
+func TestRecoveredReportsAndKeepsChain(t *testing.T) {
+	before := Default.Snapshot().Total
+
+	var err error
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				err = Recovered(r)
+			}
+		}()
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					Repanic(r, "second")
+				}
+			}()
+			panic("first")
+		}()
+	}()
+
+	var pe *PanicError
+	if !errors.As(err, &pe) {
+		t.Fatalf("Recovered returned %T, want *PanicError", err)
+	}
+	chain := pe.Chain()
+	if len(chain) != 2 || chain[0].Value != "second" || chain[1].Value != "first" {
+		t.Errorf("Chain() = %v, want second then first", chain)
+	}
+	if got := Default.Snapshot().Total; got != before+1 {
+		t.Errorf("Default total = %d, want %d", got, before+1)
+	}
+}
+
+func TestSampleIsTheValueNotTheWrapper(t *testing.T) {
+	reg := NewRegistry(10)
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				reg.Recovered(r)
+			}
+		}()
+		func() {
+			defer func() { Repanic(recover(), "outer") }()
+			panic("inner")
+		}()
+	}()
+
+	if e := reg.Snapshot().Entries[0]; e.Sample != "outer" || e.SampleType != "string" {
+		t.Errorf("sample = %s %q, want string \"outer\"", e.SampleType, e.Sample)
+	}
+}
+
+func TestOnlyIntentionalLetsBugsThrough(t *testing.T) {
+	if err := Catch(OnlyIntentional, func() { panic("abort") }); err == nil {
+		t.Error("deliberate panic was not recovered")
+	}
+
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Fatal("runtime error was recovered by OnlyIntentional")
+		}
+	}()
+	Catch(OnlyIntentional, nilDeref)
+}
+
+func TestIsBugSeesThroughRepanic(t *testing.T) {
+	err := Catch(RecoverAll, func() {
+		defer func() { Repanic(recover(), "cleanup failed") }()
+		nilDeref()
+	})
+
+	var pe *PanicError
+	if !errors.As(err, &pe) || !pe.IsBug() {
+		t.Fatalf("IsBug() = false for %v, want true", err)
+	}
+	if OnlyIntentional(pe) {
+		t.Error("OnlyIntentional accepted a chain that started with a bug")
+	}
+}
+
+func TestHandlerServesSnapshot(t *testing.T) {
+	reg := NewRegistry(10)
+	func() {
+		defer reg.Capture()
+		nilDeref()
+	}()
+
+	rec := httptest.NewRecorder()
+	reg.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/debug/panics", nil))
+
+	var snap Snapshot
+	if err := json.Unmarshal(rec.Body.Bytes(), &snap); err != nil {
+		t.Fatalf("body is not JSON: %v", err)
+	}
+	if snap.Total != 1 || len(snap.Entries[0].Stack) == 0 {
+		t.Errorf("served total %d with %d frames, want 1 with a stack", snap.Total, len(snap.Entries[0].Stack))
+	}
+}
@@ -157,6 +157,7 @@ func rangeOverStructSlice() {
 	// Important: range creates a COPY of each element
 	fmt.Println("\nModifying via range (won't work):")
 	for _, person := range people {
+		//sugarlint:ignore rangecopy shows that the copy is modified
 		person.Age += 1 // This modifies the COPY, not original!
 	}
 	fmt.Printf("  Alice's age is still: %d (unchanged)\n", people[0].Age)
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"testing"
+
+	"sugar/channels/leakcheck"
+)
+
+// Examples run one at a time: leakcheck compares goroutine snapshots, so a
+// parallel test would look like a leak.
+func TestExamplesDoNotLeakOrDeadlock(t *testing.T) {
+	leakcheck.Check(t,
+		leakcheck.Example{Name: "rangeOverSlice", Fn: rangeOverSlice},
+		leakcheck.Example{Name: "rangeOverArray", Fn: rangeOverArray},
+		leakcheck.Example{Name: "rangeOverMap", Fn: rangeOverMap},
+		leakcheck.Example{Name: "rangeOverString", Fn: rangeOverString},
+		leakcheck.Example{Name: "rangeOverChannel", Fn: rangeOverChannel},
+		leakcheck.Example{Name: "rangeWithBreakContinue", Fn: rangeWithBreakContinue},
+		leakcheck.Example{Name: "rangeOverNested", Fn: rangeOverNested},
+		leakcheck.Example{Name: "rangeOverStructSlice", Fn: rangeOverStructSlice},
+		leakcheck.Example{Name: "rangeOverMapOfSlices", Fn: rangeOverMapOfSlices},
+		leakcheck.Example{Name: "rangePerformanceNote", Fn: rangePerformanceNote},
+	)
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"iter"
+)
+
+// --- 1. Update-in-Place Helpers ---
+
+// Each yields the index and a pointer to every element of s, so writes
+// through the pointer change the slice - unlike the value copy that
+// `for _, v := range s` gives you.
+func Each[S ~[]E, E any](s S) iter.Seq2[int, *E] {
+	return func(yield func(int, *E) bool) {
+		for i := range s {
+			if !yield(i, &s[i]) {
+				return
+			}
+		}
+	}
+}
+
+// Update calls f with a pointer to every element of s.
+func Update[S ~[]E, E any](s S, f func(*E)) {
+	for i := range s {
+		f(&s[i])
+	}
+}
+
+// --- 2. Examples ---
+
+type Person struct {
+	Name string
+	Age  int
+}
+
+func newPeople() []Person {
+	return []Person{{"Alice", 25}, {"Bob", 30}, {"Charlie", 35}}
+}
+
+func helpers() {
+	fmt.Println("\n=== 1. rangeOverStructSlice, FIXED WITH Each AND Update ===")
+
+	people := newPeople()
+	for _, person := range people {
+		//sugarlint:ignore rangecopy the bug that Each and Update fix
+		person.Age += 1 // The bug: modifies the copy (the rangecopy analyzer reports this line)
+	}
+	fmt.Printf("range value copy:  Alice is %d (unchanged)\n", people[0].Age)
+
+	for _, person := range Each(people) {
+		person.Age += 1
+	}
+	fmt.Printf("Each:              Alice is %d\n", people[0].Age)
+
+	Update(people, func(p *Person) { p.Age += 1 })
+	fmt.Printf("Update:            Alice is %d\n", people[0].Age)
+
+	for i, person := range Each(people) {
+		if person.Name == "Bob" {
+			person.Name = "Robert"
+			fmt.Printf("Each with index:   renamed people[%d] to %s\n", i, people[i].Name)
+			break
+		}
+	}
+}
+
+func main() {
+	fmt.Println("✏️ GO UPDATE-IN-PLACE ITERATION - COMPLETE GUIDE")
+	fmt.Println("=================================================")
+
+	helpers()
+
+	fmt.Println("\nThe rangecopy analyzer in ./rangecopy catches the range-copy bug:")
+	fmt.Println("  go vet -vettool=$(go env GOPATH)/bin/rangecopy ./...")
+
+	fmt.Println("\n✅ All examples completed!")
+}
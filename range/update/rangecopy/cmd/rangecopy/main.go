@@ -0,0 +1,14 @@
+// The rangecopy command runs the rangecopy analyzer, standalone or as a
+// vet tool:
+//
+//	go install sugar/range/update/rangecopy/cmd/rangecopy
+//	go vet -vettool=$(go env GOPATH)/bin/rangecopy ./...
+package main
+
+import (
+	"golang.org/x/tools/go/analysis/singlechecker"
+
+	"sugar/range/update/rangecopy"
+)
+
+func main() { singlechecker.Main(rangecopy.Analyzer) }
@@ -0,0 +1,239 @@
+// Package rangecopy defines an Analyzer that reports writes to a range
+// value copy that are never read.
+//
+// In "for _, v := range s", v is a copy of the element. Assigning to
+// v.Field or v[i] changes only the copy; if v is not read afterwards, the
+// write was almost certainly meant for s[i]:
+//
+//	for _, p := range people {
+//		p.Age++ // modifies a copy of the range value and is never read
+//	}
+//
+// With "for i, v := range s" over a slice or array variable, the suggested
+// fix writes s[i].Field instead.
+package rangecopy
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+var Analyzer = &analysis.Analyzer{
+	Name: "rangecopy",
+	Doc: `report writes to a range value copy that are never read
+
+In "for _, v := range s", v is a copy of the element. Assigning to v.Field
+or v[i] changes only the copy; if v is not read afterwards, the write was
+almost certainly meant for s[i]. With "for i, v := range s" the suggested
+fix writes s[i].Field instead.`,
+	URL: "https://go.dev/ref/spec#For_range",
+	Run: run,
+}
+
+// copyWrite is an assignment to part of a range value.
+type copyWrite struct {
+	stmt  ast.Stmt
+	root  *ast.Ident // The range variable on the left-hand side
+	lhs   ast.Expr
+	scope ast.Node // Outermost loop inside the range body around the write, if any
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	for _, file := range pass.Files {
+		ast.Inspect(file, func(n ast.Node) bool {
+			if rs, ok := n.(*ast.RangeStmt); ok {
+				checkRange(pass, rs)
+			}
+			return true
+		})
+	}
+	return nil, nil
+}
+
+func checkRange(pass *analysis.Pass, rs *ast.RangeStmt) {
+	ident, ok := rs.Value.(*ast.Ident)
+	if !ok || rs.Tok != token.DEFINE {
+		return
+	}
+	v, ok := pass.TypesInfo.Defs[ident].(*types.Var)
+	if !ok {
+		return
+	}
+	switch v.Type().Underlying().(type) {
+	case *types.Struct, *types.Array:
+	default:
+		return // Pointers, slices and maps share their target: writes are visible
+	}
+
+	var writes []copyWrite
+	var reads []*ast.Ident
+	inClosure := map[*ast.Ident]bool{}
+	escaped := false
+
+	// Walk the body keeping a stack, so each write knows its enclosing loops
+	var stack []ast.Node
+	ast.Inspect(rs.Body, func(n ast.Node) bool {
+		if n == nil {
+			stack = stack[:len(stack)-1]
+			return true
+		}
+
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			for _, lhs := range n.Lhs {
+				if root := copyRoot(pass.TypesInfo, lhs, v); root != nil {
+					writes = append(writes, copyWrite{stmt: n, root: root, lhs: lhs, scope: outerLoop(stack)})
+				}
+			}
+		case *ast.IncDecStmt:
+			if root := copyRoot(pass.TypesInfo, n.X, v); root != nil {
+				writes = append(writes, copyWrite{stmt: n, root: root, lhs: n.X, scope: outerLoop(stack)})
+			}
+		case *ast.UnaryExpr:
+			if n.Op == token.AND && usesVar(pass.TypesInfo, n.X, v) {
+				escaped = true // &v or &v.f: the copy may be read through the pointer
+			}
+		case *ast.Ident:
+			if pass.TypesInfo.Uses[n] == v {
+				reads = append(reads, n)
+				for _, s := range stack {
+					if _, ok := s.(*ast.FuncLit); ok {
+						inClosure[n] = true
+					}
+				}
+			}
+		}
+		stack = append(stack, n)
+		return true
+	})
+	if escaped {
+		return
+	}
+
+	isWriteRoot := map[*ast.Ident]bool{}
+	for _, w := range writes {
+		isWriteRoot[w.root] = true
+	}
+
+	var reported []copyWrite
+	for _, w := range writes {
+		read := false
+		for _, r := range reads {
+			if isWriteRoot[r] {
+				continue
+			}
+			if r.Pos() > w.stmt.End() || inClosure[r] ||
+				(w.scope != nil && r.Pos() >= w.scope.Pos() && r.Pos() < w.scope.End()) {
+				read = true
+				break
+			}
+		}
+		if !read {
+			reported = append(reported, w)
+		}
+	}
+
+	fixable := indexable(pass.TypesInfo, rs)
+	// If every use of v is a reported write, the fix also drops v from the
+	// range clause, or the rewritten loop would not compile.
+	dropValue := len(reported) == len(reads)
+
+	for _, w := range reported {
+		d := analysis.Diagnostic{
+			Pos: w.stmt.Pos(),
+			End: w.stmt.End(),
+			Message: fmt.Sprintf("assignment to %s modifies a copy of the range value and is never read; write through the index or use Update",
+				types.ExprString(w.lhs)),
+		}
+		if fixable {
+			elem := types.ExprString(rs.X) + "[" + rs.Key.(*ast.Ident).Name + "]"
+			edits := []analysis.TextEdit{{Pos: w.root.Pos(), End: w.root.End(), NewText: []byte(elem)}}
+			if dropValue {
+				edits = append(edits, analysis.TextEdit{Pos: rs.Key.End(), End: rs.Value.End()})
+			}
+			d.SuggestedFixes = []analysis.SuggestedFix{{Message: "Write to " + elem, TextEdits: edits}}
+		}
+		pass.Report(d)
+	}
+}
+
+// indexable reports whether the fix can rewrite v.f as s[i].f: the loop
+// has an index variable and ranges over a slice, array or array pointer
+// held in a variable.
+func indexable(info *types.Info, rs *ast.RangeStmt) bool {
+	key, ok := rs.Key.(*ast.Ident)
+	if !ok || key.Name == "_" {
+		return false
+	}
+	if _, ok := ast.Unparen(rs.X).(*ast.Ident); !ok {
+		return false
+	}
+	t := info.TypeOf(rs.X).Underlying()
+	if p, ok := t.(*types.Pointer); ok {
+		t = p.Elem().Underlying()
+	}
+	switch t.(type) {
+	case *types.Slice, *types.Array:
+		return true
+	}
+	return false
+}
+
+// copyRoot returns the range variable at the root of e if e writes into the
+// copy itself - through field selectors and array indexes - and nil if e
+// reaches shared memory through a pointer, slice or map on the way.
+func copyRoot(info *types.Info, e ast.Expr, v *types.Var) *ast.Ident {
+	partial := false // e = v.x..., not a whole-value assignment
+	for {
+		switch x := e.(type) {
+		case *ast.ParenExpr:
+			e = x.X
+		case *ast.SelectorExpr:
+			if _, isPtr := info.TypeOf(x.X).Underlying().(*types.Pointer); isPtr {
+				return nil
+			}
+			e, partial = x.X, true
+		case *ast.IndexExpr:
+			if _, isArr := info.TypeOf(x.X).Underlying().(*types.Array); !isArr {
+				return nil
+			}
+			e, partial = x.X, true
+		case *ast.Ident:
+			if partial && info.Uses[x] == v {
+				return x
+			}
+			return nil
+		default:
+			return nil
+		}
+	}
+}
+
+func usesVar(info *types.Info, e ast.Expr, v *types.Var) bool {
+	found := false
+	ast.Inspect(e, func(n ast.Node) bool {
+		if id, ok := n.(*ast.Ident); ok && info.Uses[id] == v {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// outerLoop returns the outermost for or range statement on the stack -
+// the stack starts at the range body, so the analysed loop itself is not on it.
+func outerLoop(stack []ast.Node) ast.Node {
+	for _, n := range stack {
+		switch n.(type) {
+		case *ast.ForStmt, *ast.RangeStmt:
+			return n
+		case *ast.FuncLit:
+			return nil
+		}
+	}
+	return nil
+}
@@ -0,0 +1,13 @@
+package rangecopy_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"sugar/range/update/rangecopy"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.RunWithSuggestedFixes(t, analysistest.TestData(), rangecopy.Analyzer, "rangecopy")
+}
@@ -0,0 +1,102 @@
+package rangecopy
+
+import "fmt"
+
+type Person struct {
+	Name    string
+	Age     int
+	Address Address
+	Tags    []string
+}
+
+type Address struct {
+	City string
+}
+
+func birthday(people []Person) {
+	for _, person := range people {
+		person.Age += 1 // want `assignment to person.Age modifies a copy of the range value and is never read`
+	}
+}
+
+func increment(people []Person) {
+	for _, p := range people {
+		p.Age++ // want `assignment to p.Age modifies a copy`
+	}
+}
+
+func nested(people []Person) {
+	for i, p := range people {
+		p.Address.City = "Berlin" // want `assignment to p.Address.City modifies a copy`
+		_ = i
+	}
+}
+
+func mapValues(byName map[string]Person) {
+	for _, p := range byName {
+		p.Name = "anonymous" // want `assignment to p.Name modifies a copy`
+	}
+}
+
+func arrays(grid [][3]int) {
+	for _, row := range grid {
+		row[0] = 1 // want `assignment to row\[0\] modifies a copy`
+	}
+}
+
+// --- No diagnostics below ---
+
+func readAfterWrite(people []Person) {
+	for _, p := range people {
+		p.Age++
+		fmt.Println(p.Age) // The copy is used, e.g. as a scratch value
+	}
+}
+
+func indexed(people []Person) {
+	for i := range people {
+		people[i].Age++
+	}
+}
+
+func pointers(people []*Person) {
+	for _, p := range people {
+		p.Age++ // p is a pointer: this updates the element
+	}
+}
+
+func sliceField(people []Person) {
+	for _, p := range people {
+		p.Tags[0] = "vip" // The slice header is copied, the backing array is shared
+	}
+}
+
+func escapes(people []Person, keep func(*Person)) {
+	for _, p := range people {
+		p.Age++
+		keep(&p)
+	}
+}
+
+func innerLoop(people []Person) {
+	for _, p := range people {
+		for range 3 {
+			fmt.Println(p.Age) // Sees the write from the previous inner iteration
+			p.Age++
+		}
+	}
+}
+
+func closure(people []Person) {
+	for _, p := range people {
+		p.Age++
+		defer func() { fmt.Println(p.Age) }()
+	}
+}
+
+func reassigned(people []Person) {
+	for _, p := range people {
+		p = Person{Name: "x"} // Whole-value assignment is a different pattern; not reported
+		_ = p
+	}
+}
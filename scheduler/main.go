@@ -0,0 +1,568 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"sugar/clock"
+	"sugar/panics"
+)
+
+// --- 1. Schedules ---
+
+// Schedule computes the next run time after a given moment.
+type Schedule interface {
+	Next(after time.Time) time.Time
+}
+
+// Every runs a job at a fixed interval.
+type Every time.Duration
+
+func (e Every) Next(after time.Time) time.Time {
+	return after.Add(time.Duration(e))
+}
+
+// Cron is a parsed five-field cron expression: minute hour day-of-month month day-of-week.
+// Each field accepts "*", numbers, ranges ("1-5"), lists ("1,15") and steps ("*/10").
+// As in classic cron, a day field starting with "*" ("*", "*/2") counts as
+// unrestricted when the two day fields are combined.
+type Cron struct {
+	minute, hour, dom, month, dow [64]bool
+	domAny, dowAny                bool
+}
+
+// ParseCron parses an expression like "*/15 9-17 * * 1-5".
+func ParseCron(expr string) (*Cron, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron %q: want 5 fields, got %d", expr, len(fields))
+	}
+
+	c := &Cron{domAny: strings.HasPrefix(fields[2], "*"), dowAny: strings.HasPrefix(fields[4], "*")}
+	specs := []struct {
+		set      *[64]bool
+		min, max int
+	}{
+		{&c.minute, 0, 59},
+		{&c.hour, 0, 23},
+		{&c.dom, 1, 31},
+		{&c.month, 1, 12},
+		{&c.dow, 0, 6},
+	}
+	for i, spec := range specs {
+		if err := parseCronField(fields[i], spec.min, spec.max, spec.set); err != nil {
+			return nil, fmt.Errorf("cron %q: field %d: %w", expr, i+1, err)
+		}
+	}
+	return c, nil
+}
+
+func parseCronField(field string, min, max int, set *[64]bool) error {
+	for _, part := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepPart)
+			if err != nil || n <= 0 {
+				return fmt.Errorf("bad step %q", stepPart)
+			}
+			step = n
+		}
+
+		lo, hi := min, max
+		if rangePart != "*" {
+			from, to, isRange := strings.Cut(rangePart, "-")
+			var err error
+			if lo, err = strconv.Atoi(from); err != nil {
+				return fmt.Errorf("bad value %q", from)
+			}
+			hi = lo
+			if isRange {
+				if hi, err = strconv.Atoi(to); err != nil {
+					return fmt.Errorf("bad value %q", to)
+				}
+			} else if hasStep {
+				hi = max // "5/10" means 5, 15, 25, ...
+			}
+		}
+		if lo < min || hi > max || lo > hi {
+			return fmt.Errorf("range %d-%d outside %d-%d", lo, hi, min, max)
+		}
+
+		for v := lo; v <= hi; v += step {
+			set[v] = true
+		}
+	}
+	return nil
+}
+
+// Next returns the first matching minute strictly after the given time.
+func (c *Cron) Next(after time.Time) time.Time {
+	// Fields are wall-clock values, so step in wall-clock units: Truncate
+	// works on absolute time and is off in zones like Asia/Kolkata (+05:30)
+	t := time.Date(after.Year(), after.Month(), after.Day(), after.Hour(), after.Minute()+1, 0, 0, after.Location())
+
+	// Five years covers every valid expression, including Feb 29
+	for limit := t.AddDate(5, 0, 0); t.Before(limit); {
+		switch {
+		case !c.month[t.Month()]:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+		case !c.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+		case !c.hour[t.Hour()]:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+		case !c.minute[t.Minute()]:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{} // Never matches
+}
+
+// dayMatches follows classic cron: if both day fields are restricted,
+// either one matching is enough.
+func (c *Cron) dayMatches(t time.Time) bool {
+	dom, dow := c.dom[t.Day()], c.dow[t.Weekday()]
+	switch {
+	case c.domAny && c.dowAny:
+		return true
+	case c.domAny:
+		return dow
+	case c.dowAny:
+		return dom
+	default:
+		return dom || dow
+	}
+}
+
+// --- 2. Jobs ---
+
+// Overlap decides what happens when a job is due while its previous run is still going.
+type Overlap int
+
+const (
+	Skip       Overlap = iota // Drop this run
+	Queue                     // Run again as soon as the current run finishes (at most one queued)
+	Concurrent                // Start another run alongside the current one
+)
+
+// Job is a unit of periodic work.
+type Job struct {
+	Name     string
+	Schedule Schedule
+	Run      func(ctx context.Context) error
+
+	Jitter  time.Duration // Random delay up to Jitter added to every run, to spread load
+	Timeout time.Duration // Per-run deadline passed through ctx. Zero means none
+	Overlap Overlap
+
+	// OnError is called with the error or recovered panic of a failed run.
+	OnError func(name string, err error)
+}
+
+// JobInfo is the introspection view of a registered job.
+type JobInfo struct {
+	Name    string
+	NextRun time.Time
+	Running int
+	Runs    int64
+	Skipped int64
+	Failed  int64
+}
+
+type jobState struct {
+	job     Job
+	next    time.Time
+	running int
+	queued  bool
+
+	runs, skipped, failed atomic.Int64
+}
+
+// --- 3. The Scheduler ---
+
+// ErrStopped is returned when adding jobs to a stopped scheduler.
+var ErrStopped = errors.New("scheduler: stopped")
+
+// Scheduler runs jobs on their schedules. Each run gets its own goroutine,
+// context and panic handler, so one bad job can't stop the others.
+type Scheduler struct {
+	clock   clock.Clock
+	mu      sync.Mutex
+	jobs    map[string]*jobState
+	stopped bool
+
+	timer  clock.Timer     // Fires at the soonest next run, re-armed with mu held
+	quit   chan struct{}   // Closed by Stop to end the planning loop
+	ctx    context.Context // Passed to runs, cancelled only if Stop times out
+	cancel context.CancelFunc
+	loop   sync.WaitGroup // The planning goroutine
+	runs   sync.WaitGroup // Running jobs
+}
+
+// NewScheduler creates a scheduler that plans on clk and starts its
+// planning loop. Run timeouts and Stop's deadline still use real time.
+func NewScheduler(clk clock.Clock) *Scheduler {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &Scheduler{
+		clock:  clk,
+		jobs:   make(map[string]*jobState),
+		timer:  clk.NewTimer(time.Hour),
+		quit:   make(chan struct{}),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+	s.loop.Add(1)
+	go s.run()
+	return s
+}
+
+// Add registers a job. Names must be unique.
+func (s *Scheduler) Add(job Job) error {
+	if job.Name == "" || job.Schedule == nil || job.Run == nil {
+		return errors.New("scheduler: job needs a name, a schedule and a run func")
+	}
+	if e, ok := job.Schedule.(Every); ok && e <= 0 {
+		// The planning loop would find it due again immediately, forever
+		return fmt.Errorf("scheduler: job %q: interval must be positive, got %s", job.Name, time.Duration(e))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.stopped {
+		return ErrStopped
+	}
+	if _, exists := s.jobs[job.Name]; exists {
+		return fmt.Errorf("scheduler: job %q already registered", job.Name)
+	}
+
+	now := s.clock.Now()
+	st := &jobState{job: job}
+	st.next = s.plan(st, now)
+	s.jobs[job.Name] = st
+	s.rearm(now)
+	return nil
+}
+
+// plan picks the next run time, including jitter.
+func (s *Scheduler) plan(st *jobState, after time.Time) time.Time {
+	next := st.job.Schedule.Next(after)
+	if st.job.Jitter > 0 && !next.IsZero() {
+		next = next.Add(rand.N(st.job.Jitter))
+	}
+	return next
+}
+
+// Jobs returns every job with its next run time, soonest first. Jobs whose
+// schedule never fires again come last.
+func (s *Scheduler) Jobs() []JobInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	infos := make([]JobInfo, 0, len(s.jobs))
+	for _, st := range s.jobs {
+		infos = append(infos, JobInfo{
+			Name:    st.job.Name,
+			NextRun: st.next,
+			Running: st.running,
+			Runs:    st.runs.Load(),
+			Skipped: st.skipped.Load(),
+			Failed:  st.failed.Load(),
+		})
+	}
+	slices.SortFunc(infos, func(a, b JobInfo) int {
+		switch {
+		case a.NextRun.IsZero() && b.NextRun.IsZero():
+			return 0
+		case a.NextRun.IsZero():
+			return 1
+		case b.NextRun.IsZero():
+			return -1
+		}
+		return a.NextRun.Compare(b.NextRun)
+	})
+	return infos
+}
+
+func (s *Scheduler) run() {
+	defer s.loop.Done()
+	defer s.timer.Stop()
+
+	for {
+		select {
+		case <-s.timer.C():
+		case <-s.quit:
+			return
+		}
+
+		s.mu.Lock()
+		now := s.clock.Now()
+		for _, st := range s.jobs {
+			if !st.next.IsZero() && !st.next.After(now) {
+				s.dispatch(st)
+				st.next = s.plan(st, now)
+			}
+		}
+		s.rearm(now)
+		s.mu.Unlock()
+	}
+}
+
+// rearm points the timer at the soonest next run. Add and the planning loop
+// both call it with s.mu held, so a job added while the loop is busy is
+// never waited for with a stale timer. An hour is the idle poll.
+func (s *Scheduler) rearm(now time.Time) {
+	var soonest time.Time
+	for _, st := range s.jobs {
+		if st.next.IsZero() {
+			continue // Schedule never fires again
+		}
+		if soonest.IsZero() || st.next.Before(soonest) {
+			soonest = st.next
+		}
+	}
+
+	wait := time.Hour
+	if !soonest.IsZero() {
+		wait = soonest.Sub(now)
+	}
+	s.timer.Reset(wait)
+}
+
+// dispatch applies the overlap policy. Must be called with s.mu held.
+func (s *Scheduler) dispatch(st *jobState) {
+	if st.running > 0 {
+		switch st.job.Overlap {
+		case Skip:
+			st.skipped.Add(1)
+			return
+		case Queue:
+			st.queued = true
+			return
+		}
+	}
+	st.running++
+	s.runs.Add(1)
+	go s.execute(st)
+}
+
+func (s *Scheduler) execute(st *jobState) {
+	defer s.runs.Done()
+
+	for {
+		s.runOnce(st)
+
+		s.mu.Lock()
+		if st.queued && !s.stopped {
+			st.queued = false
+			s.mu.Unlock()
+			continue // A queued run starts right after this one
+		}
+		st.running--
+		s.mu.Unlock()
+		return
+	}
+}
+
+// runOnce runs the job with its timeout and turns a panic into a
+// *panics.PanicError, reported to panics.Default.
+func (s *Scheduler) runOnce(st *jobState) {
+	ctx := s.ctx
+	if st.job.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, st.job.Timeout)
+		defer cancel()
+	}
+
+	st.runs.Add(1)
+	err := func() (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = panics.Recovered(r)
+			}
+		}()
+		return st.job.Run(ctx)
+	}()
+
+	if err != nil {
+		st.failed.Add(1)
+		if st.job.OnError != nil {
+			st.job.OnError(st.job.Name, err)
+		}
+	}
+}
+
+// Stop stops scheduling new runs and waits for running jobs to return.
+// Their context is only cancelled if ctx expires first; Stop then returns
+// an error without waiting for them any longer.
+func (s *Scheduler) Stop(ctx context.Context) error {
+	s.mu.Lock()
+	if !s.stopped {
+		s.stopped = true
+		close(s.quit)
+	}
+	s.mu.Unlock()
+
+	s.loop.Wait()
+
+	done := make(chan struct{})
+	go func() {
+		s.runs.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		s.cancel() // Nothing is running, just release the context
+		return nil
+	case <-ctx.Done():
+		s.cancel() // Out of patience: ask the stragglers to give up
+		return fmt.Errorf("scheduler: jobs still running at shutdown: %w", ctx.Err())
+	}
+}
+
+// --- 4. Examples ---
+
+func logErrors(name string, err error) {
+	fmt.Printf("  [%s] error: %v\n", name, err)
+}
+
+func intervalJobsWithOverlap() {
+	fmt.Println("\n=== 1. INTERVAL JOBS AND OVERLAP POLICIES ===")
+
+	s := NewScheduler(clock.Real)
+
+	// Each run takes 250ms but is due every 100ms
+	slow := func(ctx context.Context) error {
+		select {
+		case <-time.After(250 * time.Millisecond):
+		case <-ctx.Done():
+		}
+		return nil
+	}
+
+	for _, policy := range []struct {
+		name    string
+		overlap Overlap
+	}{
+		{"skip", Skip},
+		{"queue", Queue},
+		{"concurrent", Concurrent},
+	} {
+		s.Add(Job{
+			Name:     "refresh-" + policy.name,
+			Schedule: Every(100 * time.Millisecond),
+			Run:      slow,
+			Overlap:  policy.overlap,
+		})
+	}
+
+	time.Sleep(550 * time.Millisecond)
+
+	for _, info := range s.Jobs() {
+		fmt.Printf("%-20s runs=%d skipped=%d running=%d\n", info.Name, info.Runs, info.Skipped, info.Running)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	fmt.Println("Graceful stop:", s.Stop(ctx))
+}
+
+func timeoutAndPanicIsolation() {
+	fmt.Println("\n=== 2. PER-JOB TIMEOUT AND PANIC ISOLATION ===")
+
+	s := NewScheduler(clock.Real)
+
+	var healthyRuns atomic.Int64
+	s.Add(Job{
+		Name:     "healthy",
+		Schedule: Every(50 * time.Millisecond),
+		Run: func(ctx context.Context) error {
+			healthyRuns.Add(1)
+			return nil
+		},
+	})
+	s.Add(Job{
+		Name:     "hangs",
+		Schedule: Every(200 * time.Millisecond),
+		Timeout:  30 * time.Millisecond,
+		Run: func(ctx context.Context) error {
+			<-ctx.Done() // Only the timeout gets it out
+			return ctx.Err()
+		},
+		OnError: logErrors,
+	})
+	s.Add(Job{
+		Name:     "panics",
+		Schedule: Every(200 * time.Millisecond),
+		Run: func(ctx context.Context) error {
+			var m map[string]int
+			//sugarlint:ignore nilmap a deliberate panic to show per-job isolation
+			m["boom"]++ // Assignment to entry in nil map
+			return nil
+		},
+		OnError: logErrors,
+	})
+
+	time.Sleep(300 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	s.Stop(ctx)
+	fmt.Printf("Healthy job kept running: %d runs\n", healthyRuns.Load())
+}
+
+func cronIntrospection() {
+	fmt.Println("\n=== 3. CRON SCHEDULES AND NEXT-RUN INTROSPECTION ===")
+
+	from := time.Date(2025, 3, 14, 16, 52, 0, 0, time.UTC) // A Friday
+
+	for _, expr := range []string{"*/15 * * * *", "0 9-17 * * 1-5", "30 2 1 * *", "0 0 29 2 *"} {
+		c, err := ParseCron(expr)
+		if err != nil {
+			fmt.Println("Error:", err)
+			continue
+		}
+		fmt.Printf("%-16s next after %s: %s\n", expr, from.Format("Mon 15:04"), c.Next(from).Format("Mon 2006-01-02 15:04"))
+	}
+
+	_, err := ParseCron("61 * * * *")
+	fmt.Println("Invalid expression:", err)
+
+	s := NewScheduler(clock.Real)
+	daily, _ := ParseCron("0 3 * * *")
+	s.Add(Job{Name: "nightly-backup", Schedule: daily, Run: func(context.Context) error { return nil }})
+	s.Add(Job{Name: "cache-refresh", Schedule: Every(5 * time.Minute), Jitter: 30 * time.Second, Run: func(context.Context) error { return nil }})
+
+	fmt.Println("\nRegistered jobs:")
+	for _, info := range s.Jobs() {
+		fmt.Printf("  %-15s next run in %s\n", info.Name, time.Until(info.NextRun).Round(time.Minute))
+	}
+	s.Stop(context.Background())
+}
+
+func main() {
+	fmt.Println("📅 GO JOB SCHEDULER - COMPLETE GUIDE")
+	fmt.Println("====================================")
+
+	intervalJobsWithOverlap()
+	time.Sleep(300 * time.Millisecond)
+
+	timeoutAndPanicIsolation()
+	time.Sleep(300 * time.Millisecond)
+
+	cronIntrospection()
+
+	fmt.Println("\n✅ All examples completed!")
+}
@@ -0,0 +1,380 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"sugar/channels/leakcheck"
+	"sugar/clock"
+	"sugar/panics"
+)
+
+func TestCronNextInHalfHourZone(t *testing.T) {
+	kolkata, err := time.LoadLocation("Asia/Kolkata") // UTC+05:30
+	if err != nil {
+		t.Skip("no tzdata:", err)
+	}
+	c, err := ParseCron("0 9 * * *")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	from := time.Date(2025, 3, 14, 7, 45, 0, 0, kolkata)
+	want := time.Date(2025, 3, 14, 9, 0, 0, 0, kolkata)
+	if got := c.Next(from); !got.Equal(want) {
+		t.Fatalf("Next(%s) = %s, want %s", from, got, want)
+	}
+}
+
+func TestParseCronErrors(t *testing.T) {
+	for _, expr := range []string{
+		"* * * *",     // Too few fields
+		"* * * * * *", // Too many
+		"61 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 7",
+		"5-1 * * * *", // Backwards range
+		"*/0 * * * *", // Zero step
+		"*/x * * * *",
+		"a * * * *",
+		"1-b * * * *",
+	} {
+		if _, err := ParseCron(expr); err == nil {
+			t.Errorf("ParseCron(%q) = nil error", expr)
+		}
+	}
+}
+
+func TestCronStepDayFieldIsUnrestricted(t *testing.T) {
+	from := time.Date(2025, 3, 14, 16, 52, 0, 0, time.UTC) // A Friday
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		// "*/2" days of month don't widen "Mondays" to "Mondays or odd days"
+		{"0 0 */2 * 1", time.Date(2025, 3, 17, 0, 0, 0, 0, time.UTC)},
+		// Nor do "*/2" weekdays widen "the 13th"
+		{"0 0 13 * */2", time.Date(2025, 4, 13, 0, 0, 0, 0, time.UTC)},
+		// Both restricted: either one matching is enough
+		{"0 0 13 * 1", time.Date(2025, 3, 17, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		c, err := ParseCron(tt.expr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := c.Next(from); !got.Equal(tt.want) {
+			t.Errorf("%q: Next = %s, want %s", tt.expr, got, tt.want)
+		}
+	}
+}
+
+var epoch = time.Date(2025, 3, 14, 9, 0, 0, 0, time.UTC)
+
+// advance moves clk forward and waits for the planning loop to re-arm its
+// timer, so the next Advance can't slip in while it is still planning.
+func advance(clk *clock.FakeClock, d time.Duration) {
+	clk.Advance(d)
+	clk.BlockUntil(1)
+}
+
+// blockingJob returns a run func that announces each start on started and
+// then waits for a value on release.
+func blockingJob(started, release chan struct{}) func(context.Context) error {
+	return func(context.Context) error {
+		started <- struct{}{}
+		<-release
+		return nil
+	}
+}
+
+func jobInfo(t *testing.T, s *Scheduler, name string) JobInfo {
+	t.Helper()
+	for _, info := range s.Jobs() {
+		if info.Name == name {
+			return info
+		}
+	}
+	t.Fatalf("job %q not registered", name)
+	return JobInfo{}
+}
+
+// waitIdle waits for every run of the job to return.
+func waitIdle(t *testing.T, s *Scheduler, name string) JobInfo {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for {
+		info := jobInfo(t, s, name)
+		if info.Running == 0 {
+			return info
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("%s still has %d run(s) going", name, info.Running)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestRunsOnlyWhenClockReachesSchedule(t *testing.T) {
+	clk := clock.NewFakeClock(epoch)
+	s := NewScheduler(clk)
+	defer s.Stop(context.Background())
+
+	started, release := make(chan struct{}), make(chan struct{})
+	s.Add(Job{Name: "tick", Schedule: Every(time.Second), Run: blockingJob(started, release)})
+
+	advance(clk, 999*time.Millisecond)
+	if info := jobInfo(t, s, "tick"); info.Running != 0 {
+		t.Fatalf("Running = %d before the interval passed", info.Running)
+	}
+
+	advance(clk, time.Millisecond)
+	<-started
+	release <- struct{}{}
+
+	if info := waitIdle(t, s, "tick"); info.Runs != 1 || !info.NextRun.Equal(epoch.Add(2*time.Second)) {
+		t.Errorf("Runs = %d, NextRun = %s, want 1 and %s", info.Runs, info.NextRun, epoch.Add(2*time.Second))
+	}
+}
+
+func TestOverlapQueueRunsOnceMoreAfterCurrentRun(t *testing.T) {
+	clk := clock.NewFakeClock(epoch)
+	s := NewScheduler(clk)
+	defer s.Stop(context.Background())
+
+	started, release := make(chan struct{}), make(chan struct{})
+	s.Add(Job{Name: "queue", Schedule: Every(time.Second), Overlap: Queue, Run: blockingJob(started, release)})
+
+	advance(clk, time.Second)
+	<-started
+
+	// Due twice more while the first run is going: queued, but only once
+	advance(clk, time.Second)
+	advance(clk, time.Second)
+	if info := jobInfo(t, s, "queue"); info.Running != 1 || info.Skipped != 0 {
+		t.Fatalf("Running = %d, Skipped = %d while queued, want 1, 0", info.Running, info.Skipped)
+	}
+
+	release <- struct{}{}
+	<-started // The queued run starts without another Advance
+	release <- struct{}{}
+
+	if info := waitIdle(t, s, "queue"); info.Runs != 2 {
+		t.Fatalf("Runs = %d, want the first run plus one queued run", info.Runs)
+	}
+}
+
+func TestOverlapConcurrentStartsAlongside(t *testing.T) {
+	clk := clock.NewFakeClock(epoch)
+	s := NewScheduler(clk)
+	defer s.Stop(context.Background())
+
+	started, release := make(chan struct{}), make(chan struct{})
+	s.Add(Job{Name: "concurrent", Schedule: Every(time.Second), Overlap: Concurrent, Run: blockingJob(started, release)})
+
+	advance(clk, time.Second)
+	<-started
+	advance(clk, time.Second)
+	<-started // Starts while the first is still blocked
+
+	if info := jobInfo(t, s, "concurrent"); info.Running != 2 {
+		t.Fatalf("Running = %d, want 2", info.Running)
+	}
+	release <- struct{}{}
+	release <- struct{}{}
+
+	if info := waitIdle(t, s, "concurrent"); info.Runs != 2 || info.Skipped != 0 {
+		t.Fatalf("Runs = %d, Skipped = %d, want 2, 0", info.Runs, info.Skipped)
+	}
+}
+
+func TestOverlapSkipDropsRuns(t *testing.T) {
+	clk := clock.NewFakeClock(epoch)
+	s := NewScheduler(clk)
+	defer s.Stop(context.Background())
+
+	started, release := make(chan struct{}), make(chan struct{})
+	s.Add(Job{Name: "skip", Schedule: Every(time.Second), Overlap: Skip, Run: blockingJob(started, release)})
+
+	advance(clk, time.Second)
+	<-started
+	advance(clk, time.Second)
+	advance(clk, time.Second)
+	release <- struct{}{}
+
+	if info := waitIdle(t, s, "skip"); info.Runs != 1 || info.Skipped != 2 {
+		t.Fatalf("Runs = %d, Skipped = %d, want 1, 2", info.Runs, info.Skipped)
+	}
+}
+
+func TestJitterStaysWithinBound(t *testing.T) {
+	clk := clock.NewFakeClock(epoch)
+	s := NewScheduler(clk)
+	defer s.Stop(context.Background())
+
+	const jitter = 10 * time.Second
+	earliest, latest := epoch.Add(time.Minute), epoch.Add(time.Minute+jitter)
+	for i := range 50 {
+		s.Add(Job{
+			Name:     fmt.Sprintf("job-%d", i),
+			Schedule: Every(time.Minute),
+			Jitter:   jitter,
+			Run:      func(context.Context) error { return nil },
+		})
+	}
+
+	distinct := make(map[time.Time]bool)
+	for _, info := range s.Jobs() {
+		if info.NextRun.Before(earliest) || !info.NextRun.Before(latest) {
+			t.Errorf("%s: NextRun = %s, want in [%s, %s)", info.Name, info.NextRun, earliest, latest)
+		}
+		distinct[info.NextRun] = true
+	}
+	if len(distinct) < 2 {
+		t.Error("every job got the same jitter")
+	}
+}
+
+func TestJobsSortsSoonestFirstAndNeverLast(t *testing.T) {
+	clk := clock.NewFakeClock(epoch)
+	s := NewScheduler(clk)
+	defer s.Stop(context.Background())
+
+	feb30, err := ParseCron("0 0 30 2 *") // Never fires
+	if err != nil {
+		t.Fatal(err)
+	}
+	noop := func(context.Context) error { return nil }
+	s.Add(Job{Name: "never", Schedule: feb30, Run: noop})
+	s.Add(Job{Name: "hourly", Schedule: Every(time.Hour), Run: noop})
+	s.Add(Job{Name: "minutely", Schedule: Every(time.Minute), Run: noop})
+
+	var names []string
+	for _, info := range s.Jobs() {
+		names = append(names, info.Name)
+	}
+	if want := []string{"minutely", "hourly", "never"}; !slices.Equal(names, want) {
+		t.Fatalf("Jobs() order = %v, want %v", names, want)
+	}
+}
+
+func TestAddRejectsNonPositiveInterval(t *testing.T) {
+	s := NewScheduler(clock.Real)
+	defer s.Stop(context.Background())
+
+	for _, d := range []time.Duration{0, -time.Second} {
+		err := s.Add(Job{Name: "spin", Schedule: Every(d), Run: func(context.Context) error { return nil }})
+		if err == nil {
+			t.Errorf("Add(Every(%s)) = nil, want an error", d)
+		}
+	}
+}
+
+func TestStopLetsRunningJobsFinish(t *testing.T) {
+	s := NewScheduler(clock.Real)
+
+	started := make(chan struct{})
+	result := make(chan error, 1)
+	s.Add(Job{
+		Name:     "finishes",
+		Schedule: Every(time.Millisecond),
+		Overlap:  Skip,
+		Run: func(ctx context.Context) error {
+			select {
+			case started <- struct{}{}:
+			default:
+				return nil // Only the first run reports
+			}
+			time.Sleep(50 * time.Millisecond)
+			result <- ctx.Err()
+			return nil
+		},
+	})
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := s.Stop(ctx); err != nil {
+		t.Fatalf("Stop() = %v", err)
+	}
+	if err := <-result; err != nil {
+		t.Fatalf("running job saw ctx.Err() = %v during a graceful stop", err)
+	}
+}
+
+func TestStopCancelsJobsOnTimeout(t *testing.T) {
+	s := NewScheduler(clock.Real)
+
+	started := make(chan struct{}, 1)
+	s.Add(Job{
+		Name:     "hangs",
+		Schedule: Every(time.Millisecond),
+		Overlap:  Skip, // One run at a time, the later ticks are dropped
+		Run: func(ctx context.Context) error {
+			select {
+			case started <- struct{}{}:
+			default:
+			}
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	})
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := s.Stop(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Stop() = %v, want context.DeadlineExceeded", err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		s.runs.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("job was not cancelled after Stop timed out")
+	}
+}
+
+func TestPanickingJobIsReportedToOnError(t *testing.T) {
+	s := NewScheduler(clock.Real)
+	defer s.Stop(context.Background())
+
+	got := make(chan error, 1)
+	s.Add(Job{
+		Name:     "panics",
+		Schedule: Every(time.Millisecond),
+		Overlap:  Skip,
+		Run:      func(context.Context) error { panic("job blew up") },
+		OnError: func(_ string, err error) {
+			select {
+			case got <- err:
+			default:
+			}
+		},
+	})
+
+	var pe *panics.PanicError
+	if err := <-got; !errors.As(err, &pe) || pe.Value != "job blew up" {
+		t.Fatalf("OnError got %v, want a *panics.PanicError for the job", err)
+	}
+}
+
+// Examples run one at a time: leakcheck compares goroutine snapshots, so a
+// parallel test would look like a leak.
+func TestExamplesDoNotLeakOrDeadlock(t *testing.T) {
+	leakcheck.Check(t,
+		leakcheck.Example{Name: "intervalJobsWithOverlap", Fn: intervalJobsWithOverlap},
+		leakcheck.Example{Name: "timeoutAndPanicIsolation", Fn: timeoutAndPanicIsolation},
+		leakcheck.Example{Name: "cronIntrospection", Fn: cronIntrospection},
+	)
+}
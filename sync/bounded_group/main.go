@@ -0,0 +1,382 @@
+package main
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"sugar/panics"
+)
+
+// --- 1. The Weighted Semaphore ---
+
+// Weighted limits concurrent access to a resource of a fixed total size.
+// Callers acquire as many units as their work costs, so one expensive job
+// can occupy the same capacity as several cheap ones.
+type Weighted struct {
+	size    int64
+	cur     int64
+	mu      sync.Mutex
+	waiters list.List // FIFO queue of waiter, so big requests are not starved
+}
+
+type waiter struct {
+	n     int64
+	ready chan struct{} // Closed when the semaphore is acquired
+}
+
+// NewWeighted creates a semaphore with the given total capacity.
+func NewWeighted(size int64) *Weighted {
+	if size <= 0 {
+		panic("semaphore: size must be positive")
+	}
+	return &Weighted{size: size}
+}
+
+// Acquire blocks until n units are available or ctx is done.
+func (s *Weighted) Acquire(ctx context.Context, n int64) error {
+	if n < 0 {
+		return fmt.Errorf("semaphore: negative weight %d", n)
+	}
+	if n > s.size {
+		return fmt.Errorf("semaphore: requested weight %d exceeds capacity %d", n, s.size)
+	}
+
+	// Don't hand out capacity to callers that have already given up
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	// Fast path: enough room and nobody queued in front of us
+	if s.size-s.cur >= n && s.waiters.Len() == 0 {
+		s.cur += n
+		s.mu.Unlock()
+		return nil
+	}
+
+	w := waiter{n: n, ready: make(chan struct{})}
+	elem := s.waiters.PushBack(w)
+	s.mu.Unlock()
+
+	select {
+	case <-w.ready:
+		return nil
+	case <-ctx.Done():
+		s.mu.Lock()
+		select {
+		case <-w.ready:
+			// Acquired just as ctx was cancelled - give the units back
+			s.cur -= n
+			s.notifyWaiters()
+		default:
+			isFront := s.waiters.Front() == elem
+			s.waiters.Remove(elem)
+			// Removing the front waiter may unblock the ones behind it
+			if isFront && s.size > s.cur {
+				s.notifyWaiters()
+			}
+		}
+		s.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// TryAcquire takes n units without blocking and reports whether it succeeded.
+func (s *Weighted) TryAcquire(n int64) bool {
+	if n < 0 {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.size-s.cur >= n && s.waiters.Len() == 0 {
+		s.cur += n
+		return true
+	}
+	return false
+}
+
+// Release returns n units to the semaphore.
+func (s *Weighted) Release(n int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if n < 0 || n > s.cur {
+		panic("semaphore: released more than held")
+	}
+	s.cur -= n
+	s.notifyWaiters()
+}
+
+// notifyWaiters wakes queued waiters in order while there is capacity.
+// Must be called with s.mu held.
+func (s *Weighted) notifyWaiters() {
+	for {
+		next := s.waiters.Front()
+		if next == nil {
+			return
+		}
+
+		w := next.Value.(waiter)
+		if s.size-s.cur < w.n {
+			// Stop at the first waiter that doesn't fit to keep FIFO order
+			return
+		}
+
+		s.cur += w.n
+		s.waiters.Remove(next)
+		close(w.ready)
+	}
+}
+
+// --- 2. The Bounded Group ---
+
+// BoundedGroup runs tasks on at most a fixed number of goroutines
+// and collects every error they return.
+type BoundedGroup struct {
+	sem      *Weighted
+	wg       sync.WaitGroup
+	ctx      context.Context
+	cancel   context.CancelCauseFunc
+	failFast bool
+
+	failed  atomic.Bool  // Set when fail-fast cancelled the group
+	skipped atomic.Int64 // Tasks not started because the group was cancelled
+
+	mu   sync.Mutex
+	errs []error
+}
+
+// NewBoundedGroup creates a group that runs at most limit tasks at once.
+// A limit below 1 would make every task fail, so it panics instead.
+func NewBoundedGroup(ctx context.Context, limit int) *BoundedGroup {
+	if limit <= 0 {
+		panic(fmt.Sprintf("bounded group: limit %d must be positive", limit))
+	}
+	ctx, cancel := context.WithCancelCause(ctx)
+	return &BoundedGroup{
+		sem:    NewWeighted(int64(limit)),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// FailFast makes the group stop starting new tasks after the first error.
+// Running tasks can observe this through Context(). It returns the group for chaining.
+func (g *BoundedGroup) FailFast() *BoundedGroup {
+	g.failFast = true
+	return g
+}
+
+// Context is cancelled when the group fails fast or the parent context is done.
+func (g *BoundedGroup) Context() context.Context {
+	return g.ctx
+}
+
+// Go runs fn with a weight of 1. See GoWeighted.
+func (g *BoundedGroup) Go(fn func() error) {
+	g.GoWeighted(1, fn)
+}
+
+// GoWeighted blocks until weight units of capacity are free, then runs fn
+// in a new goroutine. Blocking here (instead of inside the goroutine) is what
+// keeps the number of live goroutines bounded.
+func (g *BoundedGroup) GoWeighted(weight int64, fn func() error) {
+	if weight <= 0 {
+		// A weightless task would run outside the limit
+		g.record(fmt.Errorf("bounded group: weight %d must be positive", weight))
+		return
+	}
+	if err := g.sem.Acquire(g.ctx, weight); err != nil {
+		if ctxErr := g.ctx.Err(); ctxErr == nil || err != ctxErr {
+			g.record(err) // Not a cancellation, e.g. weight over capacity
+		} else if !g.failed.Load() {
+			g.skipped.Add(1) // Reported once by Wait, not once per task
+		}
+		// Otherwise fail-fast cancelled the group and its cause is recorded
+		return
+	}
+
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		defer g.sem.Release(weight)
+
+		if err := g.run(fn); err != nil {
+			g.record(err)
+			if g.failFast {
+				g.failed.Store(true)
+				g.cancel(err)
+			}
+		}
+	}()
+}
+
+// run calls fn and converts a panic into a *panics.PanicError, reported
+// to panics.Default so it is counted even if the caller drops the error.
+func (g *BoundedGroup) run(fn func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = panics.Recovered(r)
+		}
+	}()
+
+	return fn()
+}
+
+func (g *BoundedGroup) record(err error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.errs = append(g.errs, err)
+}
+
+// Wait blocks until all started tasks finish and returns their errors joined
+// together. Tasks skipped because the parent context was cancelled add a
+// single error with their count.
+func (g *BoundedGroup) Wait() error {
+	g.wg.Wait()
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	errs := slices.Clone(g.errs) // Appending must not write into g.errs's spare capacity
+	if n := g.skipped.Load(); n > 0 {
+		errs = append(errs, fmt.Errorf("%d tasks not started: %w", n, context.Cause(g.ctx)))
+	}
+	g.cancel(nil)
+	return errors.Join(errs...)
+}
+
+// --- 3. Examples ---
+
+func boundedFanOut() {
+	fmt.Println("\n=== 1. BOUNDED FAN-OUT ===")
+
+	var running, peak atomic.Int64
+
+	g := NewBoundedGroup(context.Background(), 4)
+	for i := 0; i < 100; i++ {
+		g.Go(func() error {
+			n := running.Add(1)
+			defer running.Add(-1)
+
+			// Track the highest number of concurrent workers
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+
+			time.Sleep(time.Millisecond)
+			if i%25 == 0 {
+				return fmt.Errorf("item %d failed", i)
+			}
+			return nil
+		})
+	}
+
+	err := g.Wait()
+	fmt.Printf("Peak concurrency: %d (limit 4)\n", peak.Load())
+	fmt.Printf("Collected errors:\n%v\n", err)
+}
+
+func failFastGroup() {
+	fmt.Println("\n=== 2. FAIL-FAST MODE ===")
+
+	var started atomic.Int64
+
+	g := NewBoundedGroup(context.Background(), 2).FailFast()
+	for i := 0; i < 50; i++ {
+		g.Go(func() error {
+			started.Add(1)
+			if i == 3 {
+				return fmt.Errorf("item %d failed", i)
+			}
+
+			select {
+			case <-time.After(10 * time.Millisecond):
+				return nil
+			case <-g.Context().Done():
+				return nil // Stop early, the group is already failing
+			}
+		})
+	}
+
+	err := g.Wait()
+	fmt.Printf("Started %d of 50 tasks before stopping\n", started.Load())
+	fmt.Println("Error:", err)
+}
+
+func panicCapture() {
+	fmt.Println("\n=== 3. PANIC CAPTURE ===")
+
+	g := NewBoundedGroup(context.Background(), 2)
+	g.Go(func() error {
+		var m map[string]int
+		//sugarlint:ignore nilmap a deliberate panic to show panic capture
+		m["boom"] = 1 // Panics: assignment to entry in nil map
+		return nil
+	})
+
+	err := g.Wait()
+
+	var pe *panics.PanicError
+	if errors.As(err, &pe) {
+		fmt.Printf("Recovered panic value: %v\n", pe.Value)
+	}
+}
+
+func weightedWork() {
+	fmt.Println("\n=== 4. WEIGHTED WORK ===")
+
+	// 10 units of capacity: large jobs take 5, small ones take 1
+	g := NewBoundedGroup(context.Background(), 10)
+
+	jobs := []struct {
+		name   string
+		weight int64
+	}{
+		{"resize-4k-image", 5},
+		{"thumbnail", 1},
+		{"resize-4k-video-frame", 5},
+		{"thumbnail", 1},
+		{"thumbnail", 1},
+	}
+
+	for _, job := range jobs {
+		g.GoWeighted(job.weight, func() error {
+			fmt.Printf("Running %s (weight %d)\n", job.name, job.weight)
+			time.Sleep(20 * time.Millisecond)
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err == nil {
+		fmt.Println("All weighted jobs finished")
+	}
+}
+
+func main() {
+	fmt.Println("🚦 GO BOUNDED GROUP - COMPLETE GUIDE")
+	fmt.Println("====================================")
+
+	boundedFanOut()
+	time.Sleep(300 * time.Millisecond)
+
+	failFastGroup()
+	time.Sleep(300 * time.Millisecond)
+
+	panicCapture()
+	time.Sleep(300 * time.Millisecond)
+
+	weightedWork()
+
+	fmt.Println("\n✅ All examples completed!")
+}
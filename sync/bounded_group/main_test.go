@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"sugar/panics"
+
+	"sugar/channels/leakcheck"
+)
+
+func TestFailFastRecordsOverweightTask(t *testing.T) {
+	g := NewBoundedGroup(context.Background(), 2).FailFast()
+	g.GoWeighted(3, func() error { return nil })
+
+	err := g.Wait()
+	if err == nil || !strings.Contains(err.Error(), "exceeds capacity") {
+		t.Fatalf("Wait() = %v, want the capacity error", err)
+	}
+}
+
+func TestFailFastRecordsParentCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	g := NewBoundedGroup(ctx, 2).FailFast()
+	g.Go(func() error { return nil })
+
+	if err := g.Wait(); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Wait() = %v, want context.Canceled", err)
+	}
+}
+
+func TestSkippedTasksReportedOnce(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	g := NewBoundedGroup(ctx, 2)
+	for range 100_000 {
+		g.Go(func() error { return nil })
+	}
+
+	err := g.Wait()
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Wait() = %v, want context.Canceled", err)
+	}
+	if got := strings.Count(err.Error(), "context canceled"); got != 1 {
+		t.Fatalf("error mentions the cancellation %d times, want once: %v", got, err)
+	}
+	if !strings.Contains(err.Error(), "100000 tasks not started") {
+		t.Fatalf("error = %v, want the skipped count", err)
+	}
+}
+
+func TestFailFastKeepsOnlyTheCause(t *testing.T) {
+	g := NewBoundedGroup(context.Background(), 1).FailFast()
+	cause := errors.New("first failure")
+	g.Go(func() error { return cause })
+	for range 10 {
+		g.Go(func() error { return nil })
+	}
+
+	if err := g.Wait(); !errors.Is(err, cause) || strings.Contains(err.Error(), "not started") {
+		t.Fatalf("Wait() = %v, want only the cause", err)
+	}
+}
+
+func TestPanicIsReturnedAndReported(t *testing.T) {
+	before := panics.Default.Snapshot().Total
+
+	g := NewBoundedGroup(context.Background(), 2)
+	g.Go(func() error { panic("task blew up") })
+
+	var pe *panics.PanicError
+	if err := g.Wait(); !errors.As(err, &pe) || pe.Value != "task blew up" {
+		t.Fatalf("Wait() = %v, want a *panics.PanicError for the task", err)
+	}
+	if got := panics.Default.Snapshot().Total; got != before+1 {
+		t.Fatalf("panics.Default total = %d, want %d", got, before+1)
+	}
+}
+
+func TestNonPositiveLimitPanics(t *testing.T) {
+	for _, limit := range []int{0, -1} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("NewBoundedGroup(ctx, %d) did not panic", limit)
+				}
+			}()
+			NewBoundedGroup(context.Background(), limit)
+		}()
+	}
+}
+
+func TestNonPositiveWeightIsRejected(t *testing.T) {
+	g := NewBoundedGroup(context.Background(), 2)
+	ran := false
+	g.GoWeighted(-1, func() error { ran = true; return nil })
+	g.GoWeighted(0, func() error { ran = true; return nil })
+
+	err := g.Wait()
+	if ran {
+		t.Error("a task with a non-positive weight ran")
+	}
+	if err == nil || strings.Count(err.Error(), "must be positive") != 2 {
+		t.Fatalf("Wait() = %v, want one error per rejected task", err)
+	}
+}
+
+func TestWeightedRejectsNegativeWeight(t *testing.T) {
+	s := NewWeighted(2)
+	if err := s.Acquire(context.Background(), -1); err == nil {
+		t.Error("Acquire(-1) = nil, want an error")
+	}
+	if s.TryAcquire(-1) {
+		t.Error("TryAcquire(-1) = true")
+	}
+	// Capacity is untouched: both units are still free
+	if !s.TryAcquire(2) {
+		t.Error("a negative request changed the semaphore's count")
+	}
+}
+
+// Examples run one at a time: leakcheck compares goroutine snapshots, so a
+// parallel test would look like a leak.
+func TestExamplesDoNotLeakOrDeadlock(t *testing.T) {
+	leakcheck.Check(t,
+		leakcheck.Example{Name: "boundedFanOut", Fn: boundedFanOut},
+		leakcheck.Example{Name: "failFastGroup", Fn: failFastGroup},
+		leakcheck.Example{Name: "panicCapture", Fn: panicCapture},
+		leakcheck.Example{Name: "weightedWork", Fn: weightedWork},
+	)
+}
@@ -0,0 +1,267 @@
+package main
+
+import (
+	"fmt"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// --- 1. Lock Statistics ---
+
+// LockStats is a snapshot of how a Guarded value's lock has been used.
+type LockStats struct {
+	Reads, Writes       int64
+	ContendedReads      int64 // Reads that had to wait for a writer
+	ContendedWrites     int64 // Writes that had to wait for anyone
+	ReadWait, WriteWait time.Duration
+	ReadHold, WriteHold time.Duration
+	LongestHold         time.Duration
+	SlowHoldsReported   int64
+}
+
+// lockStats holds the live counters. Atomics let readers update them
+// concurrently while they share the read lock.
+type lockStats struct {
+	reads, writes                   atomic.Int64
+	contendedReads, contendedWrites atomic.Int64
+	readWait, writeWait             atomic.Int64
+	readHold, writeHold             atomic.Int64
+	longestHold                     atomic.Int64
+	slowHolds                       atomic.Int64
+}
+
+// --- 2. The Guarded Container ---
+
+// Guarded protects a value of type T with a sync.RWMutex.
+// Many readers can run at once; writers get exclusive access.
+// The value is only reachable inside Read and Write, so it cannot be
+// touched without holding the lock.
+type Guarded[T any] struct {
+	mu    sync.RWMutex
+	value T
+
+	// Optional instrumentation, configured before first use
+	instrumented  bool
+	stats         lockStats
+	holdThreshold time.Duration
+	onSlowHold    func(report string)
+}
+
+// NewGuarded wraps an initial value.
+func NewGuarded[T any](value T) *Guarded[T] {
+	return &Guarded[T]{value: value}
+}
+
+// Instrument enables wait/hold time and contention tracking.
+// It returns the container for chaining.
+func (g *Guarded[T]) Instrument() *Guarded[T] {
+	g.instrumented = true
+	return g
+}
+
+// DebugSlowHolds calls report with the holder's stack whenever the lock is
+// held longer than threshold. It implies Instrument.
+func (g *Guarded[T]) DebugSlowHolds(threshold time.Duration, report func(string)) *Guarded[T] {
+	g.instrumented = true
+	g.holdThreshold = threshold
+	g.onSlowHold = report
+	return g
+}
+
+// Read calls fn with a copy of the value while holding the read lock.
+func (g *Guarded[T]) Read(fn func(T)) {
+	if !g.instrumented {
+		g.mu.RLock()
+		defer g.mu.RUnlock()
+		fn(g.value)
+		return
+	}
+
+	start := time.Now()
+	if !g.mu.TryRLock() {
+		g.stats.contendedReads.Add(1)
+		g.mu.RLock()
+	}
+	acquired := time.Now()
+	g.stats.reads.Add(1)
+	g.stats.readWait.Add(int64(acquired.Sub(start)))
+
+	stop := g.watchHold("read")
+	defer func() {
+		stop()
+		held := time.Since(acquired)
+		g.mu.RUnlock()
+		g.stats.readHold.Add(int64(held))
+		g.recordLongest(held)
+	}()
+
+	fn(g.value)
+}
+
+// Write calls fn with a pointer to the value while holding the write lock.
+func (g *Guarded[T]) Write(fn func(*T)) {
+	if !g.instrumented {
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		fn(&g.value)
+		return
+	}
+
+	start := time.Now()
+	if !g.mu.TryLock() {
+		g.stats.contendedWrites.Add(1)
+		g.mu.Lock()
+	}
+	acquired := time.Now()
+	g.stats.writes.Add(1)
+	g.stats.writeWait.Add(int64(acquired.Sub(start)))
+
+	stop := g.watchHold("write")
+	defer func() {
+		stop()
+		held := time.Since(acquired)
+		g.mu.Unlock()
+		g.stats.writeHold.Add(int64(held))
+		g.recordLongest(held)
+	}()
+
+	fn(&g.value)
+}
+
+// watchHold arms a timer that reports the current stack if the lock is
+// still held after the debug threshold. The returned func disarms it.
+func (g *Guarded[T]) watchHold(mode string) (stop func()) {
+	if g.holdThreshold <= 0 || g.onSlowHold == nil {
+		return func() {}
+	}
+
+	// Captured now, on the holder's goroutine - the timer runs on another one
+	stack := debug.Stack()
+	timer := time.AfterFunc(g.holdThreshold, func() {
+		g.stats.slowHolds.Add(1)
+		g.onSlowHold(fmt.Sprintf("%s lock held longer than %s by:\n%s", mode, g.holdThreshold, stack))
+	})
+	return func() { timer.Stop() }
+}
+
+func (g *Guarded[T]) recordLongest(held time.Duration) {
+	for {
+		cur := g.stats.longestHold.Load()
+		if int64(held) <= cur || g.stats.longestHold.CompareAndSwap(cur, int64(held)) {
+			return
+		}
+	}
+}
+
+// Stats returns a snapshot of the lock statistics.
+// All fields are zero unless the container is instrumented.
+func (g *Guarded[T]) Stats() LockStats {
+	s := &g.stats
+	return LockStats{
+		Reads:             s.reads.Load(),
+		Writes:            s.writes.Load(),
+		ContendedReads:    s.contendedReads.Load(),
+		ContendedWrites:   s.contendedWrites.Load(),
+		ReadWait:          time.Duration(s.readWait.Load()),
+		WriteWait:         time.Duration(s.writeWait.Load()),
+		ReadHold:          time.Duration(s.readHold.Load()),
+		WriteHold:         time.Duration(s.writeHold.Load()),
+		LongestHold:       time.Duration(s.longestHold.Load()),
+		SlowHoldsReported: s.slowHolds.Load(),
+	}
+}
+
+// --- 3. Examples ---
+
+type Config struct {
+	Endpoint string
+	Retries  int
+}
+
+func safeCounterWithRWMutex() {
+	fmt.Println("\n=== 1. SAFECOUNTER WITH READ LOCKS ===")
+
+	// Same as SafeCounter, but Value no longer blocks other readers
+	counter := NewGuarded(0)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 1000; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			counter.Write(func(c *int) { *c++ })
+		}()
+	}
+	wg.Wait()
+
+	counter.Read(func(c int) {
+		fmt.Println("count:", c)
+	})
+}
+
+func readHeavyConfig() {
+	fmt.Println("\n=== 2. READ-HEAVY CONFIG WITH INSTRUMENTATION ===")
+
+	cfg := NewGuarded(Config{Endpoint: "https://api.local", Retries: 3}).Instrument()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				cfg.Read(func(c Config) { _ = c.Endpoint })
+			}
+		}()
+	}
+
+	// An occasional writer while readers are busy
+	for i := 0; i < 5; i++ {
+		cfg.Write(func(c *Config) {
+			c.Retries++
+			time.Sleep(time.Millisecond)
+		})
+	}
+	wg.Wait()
+
+	s := cfg.Stats()
+	fmt.Printf("Reads: %d (contended %d), Writes: %d (contended %d)\n",
+		s.Reads, s.ContendedReads, s.Writes, s.ContendedWrites)
+	fmt.Printf("Total write hold: %s, longest hold: %s\n",
+		s.WriteHold.Round(time.Microsecond), s.LongestHold.Round(time.Microsecond))
+}
+
+func slowHolderDetection() {
+	fmt.Println("\n=== 3. DEBUG MODE - SLOW LOCK HOLDER ===")
+
+	cfg := NewGuarded(Config{}).DebugSlowHolds(50*time.Millisecond, func(report string) {
+		// Print only the first line - the full stack is long
+		first, _, _ := strings.Cut(report, "\n")
+		fmt.Println("Slow hold detected:", first)
+	})
+
+	cfg.Write(func(c *Config) {
+		time.Sleep(100 * time.Millisecond) // Simulates blocking I/O under the lock
+		c.Endpoint = "https://slow.local"
+	})
+
+	fmt.Println("Slow holds reported:", cfg.Stats().SlowHoldsReported)
+}
+
+func main() {
+	fmt.Println("🔐 GO GUARDED VALUES - COMPLETE GUIDE")
+	fmt.Println("=====================================")
+
+	safeCounterWithRWMutex()
+	time.Sleep(300 * time.Millisecond)
+
+	readHeavyConfig()
+	time.Sleep(300 * time.Millisecond)
+
+	slowHolderDetection()
+
+	fmt.Println("\n✅ All examples completed!")
+}
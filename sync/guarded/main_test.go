@@ -0,0 +1,112 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"sugar/channels/leakcheck"
+)
+
+func TestUninstrumentedHasNoStats(t *testing.T) {
+	g := NewGuarded(0)
+	g.Write(func(n *int) { *n++ })
+	g.Read(func(int) {})
+
+	if s := g.Stats(); s != (LockStats{}) {
+		t.Fatalf("Stats() = %+v, want zero without Instrument", s)
+	}
+}
+
+func TestStatsCountReadsWritesAndHolds(t *testing.T) {
+	g := NewGuarded(0).Instrument()
+	for range 3 {
+		g.Read(func(int) {})
+	}
+	g.Write(func(n *int) {
+		time.Sleep(5 * time.Millisecond)
+		*n++
+	})
+
+	s := g.Stats()
+	if s.Reads != 3 || s.Writes != 1 {
+		t.Errorf("Reads, Writes = %d, %d, want 3, 1", s.Reads, s.Writes)
+	}
+	if s.WriteHold < 5*time.Millisecond || s.LongestHold < 5*time.Millisecond {
+		t.Errorf("WriteHold = %s, LongestHold = %s, want at least 5ms", s.WriteHold, s.LongestHold)
+	}
+	if s.ContendedReads != 0 || s.ContendedWrites != 0 {
+		t.Errorf("contention %d/%d without any concurrency", s.ContendedReads, s.ContendedWrites)
+	}
+}
+
+func TestReadBehindWriterIsContended(t *testing.T) {
+	g := NewGuarded(0).Instrument()
+
+	locked := make(chan struct{})
+	release := make(chan struct{})
+	go g.Write(func(n *int) {
+		close(locked)
+		<-release
+		*n = 42
+	})
+	<-locked
+
+	got := make(chan int)
+	go g.Read(func(n int) { got <- n })
+
+	// The reader counts itself as contended before it blocks on the lock
+	for g.Stats().ContendedReads == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+
+	if n := <-got; n != 42 {
+		t.Fatalf("reader saw %d, want the writer's 42", n)
+	}
+	if s := g.Stats(); s.ReadWait <= 0 {
+		t.Errorf("ReadWait = %s for a reader that waited", s.ReadWait)
+	}
+}
+
+func TestSlowHoldIsReportedWithStack(t *testing.T) {
+	reports := make(chan string, 1)
+	g := NewGuarded(0).DebugSlowHolds(time.Millisecond, func(r string) { reports <- r })
+
+	var report string
+	g.Write(func(*int) {
+		report = <-reports // Keep holding the lock until the report arrives
+	})
+
+	if !strings.HasPrefix(report, "write lock held longer than 1ms by:") {
+		t.Errorf("report = %q", strings.SplitN(report, "\n", 2)[0])
+	}
+	if !strings.Contains(report, "TestSlowHoldIsReportedWithStack") {
+		t.Error("report's stack does not show the holder")
+	}
+	if n := g.Stats().SlowHoldsReported; n != 1 {
+		t.Errorf("SlowHoldsReported = %d, want 1", n)
+	}
+}
+
+func TestFastHoldIsNotReported(t *testing.T) {
+	g := NewGuarded(0).DebugSlowHolds(time.Hour, func(r string) {
+		t.Errorf("unexpected slow-hold report: %s", r)
+	})
+	g.Write(func(n *int) { *n++ })
+	g.Read(func(int) {})
+
+	if n := g.Stats().SlowHoldsReported; n != 0 {
+		t.Fatalf("SlowHoldsReported = %d, want 0", n)
+	}
+}
+
+// Examples run one at a time: leakcheck compares goroutine snapshots, so a
+// parallel test would look like a leak.
+func TestExamplesDoNotLeakOrDeadlock(t *testing.T) {
+	leakcheck.Check(t,
+		leakcheck.Example{Name: "safeCounterWithRWMutex", Fn: safeCounterWithRWMutex},
+		leakcheck.Example{Name: "readHeavyConfig", Fn: readHeavyConfig},
+		leakcheck.Example{Name: "slowHolderDetection", Fn: slowHolderDetection},
+	)
+}
@@ -14,3 +14,7 @@ func Process() {
 
 	// ... use buf for I/O ...
 }
+
+func main() {
+	Process()
+}
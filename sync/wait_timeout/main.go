@@ -0,0 +1,226 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// --- 1. The Tracked WaitGroup ---
+
+// TrackedWaitGroup works like sync.WaitGroup, but it knows which tasks are
+// still running and can stop waiting after a timeout.
+type TrackedWaitGroup struct {
+	mu      sync.Mutex
+	nextID  int
+	running map[int]task
+	idle    chan struct{} // Closed whenever no tasks are outstanding
+}
+
+type task struct {
+	name    string
+	started time.Time
+}
+
+// NewTrackedWaitGroup creates an empty group.
+func NewTrackedWaitGroup() *TrackedWaitGroup {
+	idle := make(chan struct{})
+	close(idle)
+	return &TrackedWaitGroup{
+		running: make(map[int]task),
+		idle:    idle,
+	}
+}
+
+// Add registers one named task and returns the function that marks it done.
+// Calling the returned function more than once has no effect.
+func (g *TrackedWaitGroup) Add(name string) (done func()) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	// Going from idle to busy: Wait callers need a fresh channel to block on
+	if len(g.running) == 0 {
+		g.idle = make(chan struct{})
+	}
+
+	id := g.nextID
+	g.nextID++
+	g.running[id] = task{name: name, started: time.Now()}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { g.finish(id) })
+	}
+}
+
+// Go runs fn in a new goroutine tracked under name.
+func (g *TrackedWaitGroup) Go(name string, fn func()) {
+	done := g.Add(name)
+	go func() {
+		defer done()
+		fn()
+	}()
+}
+
+func (g *TrackedWaitGroup) finish(id int) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	delete(g.running, id)
+	if len(g.running) == 0 {
+		close(g.idle)
+	}
+}
+
+// Pending returns the number of tasks that have not finished yet.
+func (g *TrackedWaitGroup) Pending() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return len(g.running)
+}
+
+// Running describes the unfinished tasks, oldest first, e.g. "db-flush (running 2s)".
+func (g *TrackedWaitGroup) Running() []string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	tasks := make([]task, 0, len(g.running))
+	for _, t := range g.running {
+		tasks = append(tasks, t)
+	}
+	slices.SortFunc(tasks, func(a, b task) int {
+		return a.started.Compare(b.started)
+	})
+
+	out := make([]string, len(tasks))
+	for i, t := range tasks {
+		out[i] = fmt.Sprintf("%s (running %s)", t.name, time.Since(t.started).Round(time.Millisecond))
+	}
+	return out
+}
+
+// Wait blocks until every task is done, like sync.WaitGroup.Wait.
+func (g *TrackedWaitGroup) Wait() {
+	_ = g.WaitContext(context.Background())
+}
+
+// WaitTimeout waits at most d. See WaitContext.
+func (g *TrackedWaitGroup) WaitTimeout(d time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), d)
+	defer cancel()
+	return g.WaitContext(ctx)
+}
+
+// WaitContext blocks until every task is done or ctx is done.
+// When ctx wins it returns a *StillRunningError listing the unfinished tasks.
+func (g *TrackedWaitGroup) WaitContext(ctx context.Context) error {
+	g.mu.Lock()
+	idle := g.idle
+	g.mu.Unlock()
+
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return &StillRunningError{Cause: ctx.Err(), Tasks: g.Running()}
+	}
+}
+
+// --- 2. The Timeout Error ---
+
+// StillRunningError reports which tasks were still running when waiting gave up.
+type StillRunningError struct {
+	Cause error
+	Tasks []string
+}
+
+func (e *StillRunningError) Error() string {
+	return fmt.Sprintf("%v: %d task(s) still running: %s", e.Cause, len(e.Tasks), strings.Join(e.Tasks, ", "))
+}
+
+func (e *StillRunningError) Unwrap() error {
+	return e.Cause
+}
+
+// --- 3. Examples ---
+
+func waitAllFinish() {
+	fmt.Println("\n=== 1. ALL TASKS FINISH IN TIME ===")
+
+	g := NewTrackedWaitGroup()
+	for i := 1; i <= 3; i++ {
+		g.Go(fmt.Sprintf("worker-%d", i), func() {
+			time.Sleep(time.Duration(i) * 50 * time.Millisecond)
+		})
+	}
+
+	fmt.Printf("Pending right after start: %d\n", g.Pending())
+
+	if err := g.WaitTimeout(time.Second); err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	fmt.Printf("All done, pending: %d\n", g.Pending())
+}
+
+func waitWithHangingWorker() {
+	fmt.Println("\n=== 2. HANGING WORKER IS REPORTED ===")
+
+	g := NewTrackedWaitGroup()
+	stuck := make(chan struct{})
+
+	g.Go("cache-refresh", func() {
+		time.Sleep(20 * time.Millisecond)
+	})
+	g.Go("db-flush", func() {
+		<-stuck // Simulates a worker that never returns
+	})
+
+	// Instead of blocking forever like wg.Wait(), we find out who is stuck
+	if err := g.WaitTimeout(200 * time.Millisecond); err != nil {
+		fmt.Println("Shutdown timed out:", err)
+	}
+
+	close(stuck)
+	g.Wait()
+	fmt.Println("Stuck worker released, pending:", g.Pending())
+}
+
+func manualAddDone() {
+	fmt.Println("\n=== 3. MANUAL ADD / DONE ===")
+
+	g := NewTrackedWaitGroup()
+
+	done := g.Add("http-server")
+	go func() {
+		defer done()
+		time.Sleep(50 * time.Millisecond)
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel() // Already cancelled: WaitContext returns immediately
+
+	err := g.WaitContext(ctx)
+	fmt.Println("WaitContext with cancelled ctx:", err)
+
+	g.Wait()
+	fmt.Println("Server stopped")
+}
+
+func main() {
+	fmt.Println("⏱️ GO WAITGROUP WITH TIMEOUT - COMPLETE GUIDE")
+	fmt.Println("=============================================")
+
+	waitAllFinish()
+	time.Sleep(300 * time.Millisecond)
+
+	waitWithHangingWorker()
+	time.Sleep(300 * time.Millisecond)
+
+	manualAddDone()
+
+	fmt.Println("\n✅ All examples completed!")
+}
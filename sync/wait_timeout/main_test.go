@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"sugar/channels/leakcheck"
+)
+
+func TestWaitTimeoutReturnsNilWhenAllFinish(t *testing.T) {
+	g := NewTrackedWaitGroup()
+	for range 3 {
+		g.Go("quick", func() {})
+	}
+
+	if err := g.WaitTimeout(time.Second); err != nil {
+		t.Fatalf("WaitTimeout() = %v, want nil", err)
+	}
+	if n := g.Pending(); n != 0 {
+		t.Fatalf("Pending() = %d after Wait, want 0", n)
+	}
+}
+
+func TestWaitTimeoutNamesStillRunningTasks(t *testing.T) {
+	g := NewTrackedWaitGroup()
+	release := make(chan struct{})
+	defer close(release)
+
+	g.Go("db-flush", func() { <-release })
+	time.Sleep(time.Millisecond) // Distinct start times, so the order is fixed
+	g.Go("cache-warm", func() { <-release })
+	finished := g.Add("finished")
+	finished()
+
+	err := g.WaitTimeout(20 * time.Millisecond)
+
+	var sre *StillRunningError
+	if !errors.As(err, &sre) {
+		t.Fatalf("WaitTimeout() = %v, want a *StillRunningError", err)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("errors.Is(err, context.DeadlineExceeded) = false for %v", err)
+	}
+	if len(sre.Tasks) != 2 {
+		t.Fatalf("Tasks = %v, want the 2 unfinished tasks", sre.Tasks)
+	}
+	// Oldest first, and only the ones still running
+	if !strings.HasPrefix(sre.Tasks[0], "db-flush (running ") || !strings.HasPrefix(sre.Tasks[1], "cache-warm (running ") {
+		t.Errorf("Tasks = %v, want db-flush then cache-warm", sre.Tasks)
+	}
+	if !strings.Contains(err.Error(), "2 task(s) still running") {
+		t.Errorf("Error() = %q, want the count", err)
+	}
+}
+
+func TestWaitContextCancelled(t *testing.T) {
+	g := NewTrackedWaitGroup()
+	done := g.Add("server")
+	defer done()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := g.WaitContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("WaitContext() = %v, want context.Canceled", err)
+	}
+}
+
+func TestDoneTwiceAndReuse(t *testing.T) {
+	g := NewTrackedWaitGroup()
+	first := g.Add("first")
+	second := g.Add("second")
+	first()
+	first() // No effect: must not finish "second"
+
+	if n := g.Pending(); n != 1 {
+		t.Fatalf("Pending() = %d after calling done twice, want 1", n)
+	}
+	second()
+	if err := g.WaitTimeout(time.Second); err != nil {
+		t.Fatalf("WaitTimeout() = %v once idle", err)
+	}
+
+	// Busy again after being idle: Wait must block on the new task
+	third := g.Add("third")
+	if err := g.WaitTimeout(10 * time.Millisecond); err == nil {
+		t.Fatal("WaitTimeout() = nil while a new task is running")
+	}
+	third()
+}
+
+// Examples run one at a time: leakcheck compares goroutine snapshots, so a
+// parallel test would look like a leak.
+func TestExamplesDoNotLeakOrDeadlock(t *testing.T) {
+	leakcheck.Check(t,
+		leakcheck.Example{Name: "waitAllFinish", Fn: waitAllFinish},
+		leakcheck.Example{Name: "waitWithHangingWorker", Fn: waitWithHangingWorker},
+		leakcheck.Example{Name: "manualAddDone", Fn: manualAddDone},
+	)
+}
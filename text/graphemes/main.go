@@ -0,0 +1,487 @@
+package main
+
+import (
+	"bufio"
+	"embed"
+	"fmt"
+	"iter"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// --- 1. Vendored Unicode Tables ---
+
+// The ucd directory holds Unicode Character Database files in their
+// original format. They are reduced subsets - see the header of each file -
+// but the parser accepts the full files as published.
+//
+//go:embed ucd/*.txt
+var ucd embed.FS
+
+// rangeEntry maps lo..hi (inclusive) to a property value.
+type rangeEntry struct {
+	lo, hi rune
+	value  string
+}
+
+// table is a sorted list of ranges.
+type table []rangeEntry
+
+// lookup returns the value for r, or "" if r is not in the table.
+func (t table) lookup(r rune) string {
+	i := sort.Search(len(t), func(i int) bool { return t[i].hi >= r })
+	if i < len(t) && t[i].lo <= r {
+		return t[i].value
+	}
+	return ""
+}
+
+// loadTable parses lines like "1F1E6..1F1FF ; Regional_Indicator # ...".
+// If keep is non-nil, only the listed property values are loaded.
+func loadTable(name string, keep ...string) table {
+	f, err := ucd.Open("ucd/" + name)
+	if err != nil {
+		panic(err) // The files are embedded; a miss is a build problem
+	}
+	defer f.Close()
+
+	var t table
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		line, _, _ := strings.Cut(sc.Text(), "#")
+		codes, value, ok := strings.Cut(line, ";")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if len(keep) > 0 && !contains(keep, value) {
+			continue
+		}
+
+		loStr, hiStr, isRange := strings.Cut(strings.TrimSpace(codes), "..")
+		if !isRange {
+			hiStr = loStr
+		}
+		lo, err1 := strconv.ParseUint(loStr, 16, 32)
+		hi, err2 := strconv.ParseUint(hiStr, 16, 32)
+		if err1 != nil || err2 != nil {
+			panic(fmt.Sprintf("ucd/%s: bad line %q", name, sc.Text()))
+		}
+		t = append(t, rangeEntry{rune(lo), rune(hi), value})
+	}
+	sort.Slice(t, func(i, j int) bool { return t[i].lo < t[j].lo })
+	return t
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	graphemeBreakTable = loadTable("GraphemeBreakProperty.txt")
+	pictographicTable  = loadTable("emoji-data.txt", "Extended_Pictographic")
+	wideTable          = loadTable("EastAsianWidth.txt", "W", "F")
+	conjunctTable      = loadTable("DerivedCoreProperties.txt", "InCB; Consonant", "InCB; Linker", "InCB; Extend")
+)
+
+// --- 2. Grapheme Break Properties ---
+
+// breakProp is a Grapheme_Cluster_Break property value (UAX #29).
+type breakProp uint8
+
+const (
+	propOther breakProp = iota
+	propCR
+	propLF
+	propControl
+	propExtend
+	propZWJ
+	propRegionalIndicator
+	propPrepend
+	propSpacingMark
+	propL
+	propV
+	propT
+	propLV
+	propLVT
+)
+
+var propNames = map[string]breakProp{
+	"CR": propCR, "LF": propLF, "Control": propControl, "Extend": propExtend,
+	"ZWJ": propZWJ, "Regional_Indicator": propRegionalIndicator, "Prepend": propPrepend,
+	"SpacingMark": propSpacingMark, "L": propL, "V": propV, "T": propT, "LV": propLV, "LVT": propLVT,
+}
+
+func propertyOf(r rune) breakProp {
+	if name := graphemeBreakTable.lookup(r); name != "" {
+		return propNames[name]
+	}
+	// Hangul syllables: every 28th one starting at U+AC00 has no final consonant
+	if r >= 0xAC00 && r <= 0xD7A3 {
+		if (r-0xAC00)%28 == 0 {
+			return propLV
+		}
+		return propLVT
+	}
+	// The rest follows from the general category, which Go ships in full
+	switch {
+	case unicode.In(r, unicode.Mn, unicode.Me):
+		return propExtend
+	case unicode.Is(unicode.Mc, r):
+		return propSpacingMark
+	case unicode.In(r, unicode.Cc, unicode.Cf, unicode.Zl, unicode.Zp):
+		return propControl
+	}
+	return propOther
+}
+
+func isPictographic(r rune) bool {
+	return pictographicTable.lookup(r) != ""
+}
+
+// conjunctState tracks rule GB9c: an Indic consonant, then Extend or Linker
+// marks with at least one Linker, joins the next consonant.
+type conjunctState uint8
+
+const (
+	conjunctNone      conjunctState = iota
+	conjunctConsonant               // Consonant [Extend Linker]*
+	conjunctLinked                  // ...with a Linker among them
+)
+
+// --- 3. Grapheme Cluster Iteration ---
+
+// breakState carries what the UAX #29 rules need to know about the
+// cluster so far.
+type breakState struct {
+	prev      breakProp
+	inPict    bool // Cluster ends in Extended_Pictographic Extend*
+	pictZWJ   bool // ...followed by a ZWJ (rule GB11)
+	riCount   int  // Regional indicators in a row (rules GB12, GB13)
+	conjunct  conjunctState
+	firstRune bool
+}
+
+// breaksBefore reports whether there is a cluster boundary before r,
+// and advances the state past r.
+func (st *breakState) breaksBefore(r rune) bool {
+	cur := propertyOf(r)
+	pict := isPictographic(r)
+	incb := conjunctTable.lookup(r)
+
+	joinsConjunct := st.conjunct == conjunctLinked && incb == "InCB; Consonant"
+	brk := st.firstRune || boundary(st.prev, cur, joinsConjunct, st.pictZWJ && pict, st.riCount)
+
+	// Advance
+	switch {
+	case incb == "InCB; Consonant":
+		st.conjunct = conjunctConsonant
+	case incb == "InCB; Linker" && st.conjunct != conjunctNone:
+		st.conjunct = conjunctLinked
+	case incb != "InCB; Extend":
+		st.conjunct = conjunctNone
+	}
+	st.pictZWJ = cur == propZWJ && st.inPict
+	switch {
+	case pict:
+		st.inPict = true
+	case cur != propExtend:
+		st.inPict = false
+	}
+	if cur == propRegionalIndicator {
+		st.riCount++
+	} else {
+		st.riCount = 0
+	}
+	st.prev = cur
+	st.firstRune = false
+	return brk
+}
+
+// boundary applies the UAX #29 rules GB3-GB999 in order, as of Unicode 15.1.
+func boundary(prev, cur breakProp, joinsConjunct, joinsPict bool, riCount int) bool {
+	isCtl := func(p breakProp) bool { return p == propCR || p == propLF || p == propControl }
+
+	switch {
+	case prev == propCR && cur == propLF: // GB3
+		return false
+	case isCtl(prev) || isCtl(cur): // GB4, GB5
+		return true
+	case prev == propL && (cur == propL || cur == propV || cur == propLV || cur == propLVT): // GB6
+		return false
+	case (prev == propLV || prev == propV) && (cur == propV || cur == propT): // GB7
+		return false
+	case (prev == propLVT || prev == propT) && cur == propT: // GB8
+		return false
+	case cur == propExtend || cur == propZWJ: // GB9
+		return false
+	case cur == propSpacingMark: // GB9a
+		return false
+	case prev == propPrepend: // GB9b
+		return false
+	case joinsConjunct: // GB9c
+		return false
+	case prev == propZWJ && joinsPict: // GB11
+		return false
+	case prev == propRegionalIndicator && cur == propRegionalIndicator: // GB12, GB13
+		return riCount%2 == 0
+	}
+	return true // GB999
+}
+
+// Graphemes yields each user-perceived character of s with its byte offset.
+func Graphemes(s string) iter.Seq2[int, string] {
+	return func(yield func(int, string) bool) {
+		st := breakState{firstRune: true}
+		start := 0
+		for i, r := range s {
+			if st.breaksBefore(r) && i > start {
+				if !yield(start, s[start:i]) {
+					return
+				}
+				start = i
+			}
+		}
+		if start < len(s) {
+			yield(start, s[start:])
+		}
+	}
+}
+
+// GraphemeCount returns the number of user-perceived characters in s.
+func GraphemeCount(s string) int {
+	n := 0
+	for range Graphemes(s) {
+		n++
+	}
+	return n
+}
+
+// --- 4. Rune and Byte Indexes ---
+
+// RuneToByte returns the byte offset of the rune at runeIndex, or -1 if s
+// has fewer runes. This is the mapping rangeOverString prints by hand.
+func RuneToByte(s string, runeIndex int) int {
+	n := 0
+	for i := range s {
+		if n == runeIndex {
+			return i
+		}
+		n++
+	}
+	if n == runeIndex {
+		return len(s) // One past the end, like len() for byte slices
+	}
+	return -1
+}
+
+// ByteToRune returns the index of the rune containing byte offset b.
+func ByteToRune(s string, b int) int {
+	if b < 0 || b > len(s) {
+		return -1
+	}
+	// Step back to the first byte of the rune b falls in
+	for b > 0 && b < len(s) && !utf8.RuneStart(s[b]) {
+		b--
+	}
+	return utf8.RuneCountInString(s[:b])
+}
+
+// --- 5. Display Width ---
+
+// GraphemeWidth returns how many terminal columns one cluster takes.
+func GraphemeWidth(g string) int {
+	r, _ := utf8.DecodeRuneInString(g)
+	switch {
+	case g == "":
+		return 0
+	case propertyOf(r) == propRegionalIndicator:
+		return 2 // A flag, or a lone indicator shown as a boxed letter
+	case isPictographic(r) && strings.ContainsRune(g, '\uFE0F'):
+		return 2 // VS16 asks for emoji presentation
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf, unicode.Cc):
+		return 0
+	case wideTable.lookup(r) != "":
+		return 2
+	}
+	return 1
+}
+
+// Width returns how many terminal columns s takes.
+func Width(s string) int {
+	w := 0
+	for _, g := range Graphemes(s) {
+		w += GraphemeWidth(g)
+	}
+	return w
+}
+
+// --- 6. Safe Truncation and Padding ---
+
+// TruncateGraphemes keeps the first n clusters, appending tail if anything
+// was cut. A cluster is never split.
+func TruncateGraphemes(s string, n int, tail string) string {
+	count := 0
+	for i := range Graphemes(s) {
+		if count == n {
+			return s[:i] + tail
+		}
+		count++
+	}
+	return s
+}
+
+// TruncateWidth cuts s so that it fits in cols columns, tail included.
+// A wide character that would straddle the limit is dropped whole. If the
+// tail alone is wider than cols, it is left out.
+func TruncateWidth(s string, cols int, tail string) string {
+	if Width(s) <= cols {
+		return s
+	}
+	if Width(tail) > cols {
+		tail = ""
+	}
+	limit := cols - Width(tail)
+	w := 0
+	for i, g := range Graphemes(s) {
+		gw := GraphemeWidth(g)
+		if w+gw > limit {
+			return s[:i] + tail
+		}
+		w += gw
+	}
+	return s
+}
+
+// PadRight pads s with spaces to cols columns. Use it instead of %-10s,
+// which counts bytes, not columns.
+func PadRight(s string, cols int) string {
+	if w := Width(s); w < cols {
+		return s + strings.Repeat(" ", cols-w)
+	}
+	return s
+}
+
+// --- 7. Examples ---
+
+func rangeOverStringRevisited() {
+	fmt.Println("\n=== 1. rangeOverString, ONE LEVEL UP ===")
+
+	text := "Hello, 世界"
+	fmt.Printf("%q: %d bytes, %d runes, %d graphemes, %d columns\n",
+		text, len(text), utf8.RuneCountInString(text), GraphemeCount(text), Width(text))
+
+	for i, g := range Graphemes(text) {
+		if GraphemeWidth(g) == 2 {
+			fmt.Printf("  byte %d: %s (rune %d, 2 columns)\n", i, g, ByteToRune(text, i))
+		}
+	}
+}
+
+func clusterExamples() {
+	fmt.Println("\n=== 2. WHAT A USER SEES AS ONE CHARACTER ===")
+
+	cases := []struct {
+		name      string
+		text      string
+		graphemes int // Expected, per UAX #29
+		width     int
+	}{
+		{"combining accent", "e\u0301", 1, 1},
+		{"skin tone modifier", "\U0001F44D\U0001F3FD", 1, 2},
+		{"ZWJ family", "\U0001F468\u200D\U0001F469\u200D\U0001F467", 1, 2},
+		{"two flags", "\U0001F1EF\U0001F1F5\U0001F1FA\U0001F1F8", 2, 4},
+		{"emoji + VS16", "\u2764\uFE0F", 1, 2},
+		{"Hangul jamo", "\u1112\u1161\u11AB", 1, 2},
+		{"CRLF", "\r\n", 1, 0},
+		{"Devanagari", "\u0928\u092E\u0938\u094D\u0924\u0947", 3, 3}, // GB9c: स्ते is one conjunct cluster
+	}
+
+	fmt.Printf("%-20s %5s %5s %9s %5s\n", "", "bytes", "runes", "graphemes", "width")
+	for _, c := range cases {
+		g, w := GraphemeCount(c.text), Width(c.text)
+		mark := "ok"
+		if g != c.graphemes || w != c.width {
+			mark = fmt.Sprintf("EXPECTED %d/%d", c.graphemes, c.width)
+		}
+		fmt.Printf("%-20s %5d %5d %9d %5d  %s\n", c.name, len(c.text), utf8.RuneCountInString(c.text), g, w, mark)
+	}
+}
+
+func indexMapping() {
+	fmt.Println("\n=== 3. RUNE INDEX <-> BYTE INDEX ===")
+
+	text := "naïve café 世界"
+	for _, ri := range []int{2, 3, 9, 11, 12} {
+		b := RuneToByte(text, ri)
+		r, _ := utf8.DecodeRuneInString(text[b:])
+		fmt.Printf("rune %2d -> byte %2d (%c)\n", ri, b, r)
+	}
+	fmt.Printf("byte 14 (middle of 世) -> rune %d\n", ByteToRune(text, 14))
+}
+
+func truncation() {
+	fmt.Println("\n=== 4. SAFE TRUNCATION ===")
+
+	name := "José 👨‍👩‍👧 Müller-世界"
+
+	naive := name[:7]
+	fmt.Printf("name[:7]:               %q (valid UTF-8: %t)\n", naive, utf8.ValidString(naive))
+	fmt.Printf("TruncateGraphemes(6):   %q\n", TruncateGraphemes(name, 6, "…"))
+	for _, cols := range []int{8, 12, 20} {
+		t := TruncateWidth(name, cols, "…")
+		fmt.Printf("TruncateWidth(%2d):      %q -> %d columns\n", cols, t, Width(t))
+	}
+}
+
+func alignedTable() {
+	fmt.Println("\n=== 5. CLI TABLE ALIGNMENT ===")
+
+	users := []struct{ name, role string }{
+		{"Alice", "admin"},
+		{"José", "editor"},
+		{"山田太郎", "viewer"},
+		{"Zoë 👍🏽", "editor"},
+		{"Christopher Columbus", "guest"},
+	}
+
+	fmt.Println("With fmt width (counts runes):")
+	for _, u := range users {
+		fmt.Printf("  |%-12s|%-8s|\n", u.name, u.role)
+	}
+
+	fmt.Println("With PadRight + TruncateWidth (counts columns):")
+	for _, u := range users {
+		fmt.Printf("  |%s|%-8s|\n", PadRight(TruncateWidth(u.name, 12, "…"), 12), u.role)
+	}
+}
+
+func main() {
+	fmt.Println("🔤 GO UNICODE GRAPHEMES AND WIDTH - COMPLETE GUIDE")
+	fmt.Println("==================================================")
+
+	rangeOverStringRevisited()
+	time.Sleep(300 * time.Millisecond)
+
+	clusterExamples()
+	time.Sleep(300 * time.Millisecond)
+
+	indexMapping()
+	time.Sleep(300 * time.Millisecond)
+
+	truncation()
+	time.Sleep(300 * time.Millisecond)
+
+	alignedTable()
+
+	fmt.Println("\n✅ All examples completed!")
+}
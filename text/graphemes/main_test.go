@@ -0,0 +1,149 @@
+package main
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"sugar/channels/leakcheck"
+)
+
+// graphemeBreakTests use the format of the Unicode 15.1.0 GraphemeBreakTest.txt
+// (https://www.unicode.org/Public/15.1.0/ucd/auxiliary/GraphemeBreakTest.txt),
+// with cases for every rule written against the vendored tables, so the full
+// file's lines can be pasted in. ÷ marks a boundary and × no boundary.
+var graphemeBreakTests = []string{
+	"÷ 000D × 000A ÷",                          // GB3
+	"÷ 000A ÷ 000D ÷",                          // GB4
+	"÷ 0061 ÷ 0001 ÷ 0062 ÷",                   // GB4, GB5
+	"÷ 1100 × 1161 × 11A8 ÷",                   // GB6, GB7
+	"÷ AC00 × 11A8 ÷ 1100 ÷",                   // GB7, GB999
+	"÷ AC01 × 11A8 ÷ 0061 ÷",                   // GB8
+	"÷ 0020 × 0308 ÷ 0020 ÷",                   // GB9
+	"÷ 0061 × 200D ÷ 0062 ÷",                   // GB9
+	"÷ 0061 × 0903 ÷ 0062 ÷",                   // GB9a
+	"÷ 0600 × 0020 ÷",                          // GB9b
+	"÷ 0915 × 094D × 0924 ÷",                   // GB9c
+	"÷ 0915 × 094D × 094D × 0924 ÷",            // GB9c
+	"÷ 0915 × 093C × 094D × 0924 ÷",            // GB9c
+	"÷ 0915 × 094D × 200D × 0924 ÷",            // GB9c
+	"÷ 0915 × 094D × 0924 × 094D × 092F ÷",     // GB9c
+	"÷ 0915 ÷ 0924 ÷",                          // GB9c needs a Linker
+	"÷ 0915 × 094D ÷ 0061 ÷",                   // GB9c needs a consonant after
+	"÷ 0061 × 094D ÷ 0924 ÷",                   // GB9c needs a consonant before
+	"÷ 0915 × 0941 × 094D ÷ 0924 ÷",            // 0941 is not InCB=Extend
+	"÷ 1F476 × 1F3FF ÷ 1F476 ÷",                // GB9
+	"÷ 1F6D1 × 200D × 1F6D1 ÷",                 // GB11
+	"÷ 2701 × 200D × 2701 ÷",                   // GB11
+	"÷ 1F6D1 × 0308 × 200D × 1F6D1 ÷",          // GB11
+	"÷ 0061 × 200D ÷ 1F6D1 ÷",                  // GB11 needs a pictograph before
+	"÷ 1F1E6 × 1F1E7 ÷ 1F1E8 ÷ 0062 ÷",         // GB12
+	"÷ 0061 ÷ 1F1E6 × 1F1E7 ÷ 1F1E8 × 1F1E9 ÷", // GB13
+}
+
+// parseBreakTest turns a GraphemeBreakTest.txt line into its text and the
+// clusters it should split into.
+func parseBreakTest(t *testing.T, line string) (string, []string) {
+	t.Helper()
+	var text, cluster strings.Builder
+	var clusters []string
+	for _, f := range strings.Fields(line) {
+		switch f {
+		case "÷":
+			if cluster.Len() > 0 {
+				clusters = append(clusters, cluster.String())
+				cluster.Reset()
+			}
+		case "×":
+		default:
+			cp, err := strconv.ParseUint(f, 16, 32)
+			if err != nil {
+				t.Fatalf("bad code point %q in %q", f, line)
+			}
+			text.WriteRune(rune(cp))
+			cluster.WriteRune(rune(cp))
+		}
+	}
+	return text.String(), clusters
+}
+
+func TestGraphemeBreakTest(t *testing.T) {
+	for _, line := range graphemeBreakTests {
+		text, want := parseBreakTest(t, line)
+		var got []string
+		for _, g := range Graphemes(text) {
+			got = append(got, g)
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("%s: got %+q, want %+q", line, got, want)
+		}
+	}
+}
+
+func TestClusterCountAndWidth(t *testing.T) {
+	tests := []struct {
+		name      string
+		text      string
+		graphemes int
+		width     int
+	}{
+		{"ASCII", "hello", 5, 5},
+		{"combining accent", "é", 1, 1},
+		{"skin tone modifier", "\U0001F44D\U0001F3FD", 1, 2},
+		{"ZWJ family", "\U0001F468‍\U0001F469‍\U0001F467", 1, 2},
+		{"two flags", "\U0001F1EF\U0001F1F5\U0001F1FA\U0001F1F8", 2, 4},
+		{"emoji + VS16", "❤️", 1, 2},
+		{"Hangul jamo", "한", 1, 2},
+		{"CRLF", "\r\n", 1, 0},
+		{"Devanagari", "नमस्ते", 3, 3},
+		{"CJK", "世界", 2, 4},
+	}
+	for _, tt := range tests {
+		if g := GraphemeCount(tt.text); g != tt.graphemes {
+			t.Errorf("%s: GraphemeCount = %d, want %d", tt.name, g, tt.graphemes)
+		}
+		if w := Width(tt.text); w != tt.width {
+			t.Errorf("%s: Width = %d, want %d", tt.name, w, tt.width)
+		}
+	}
+}
+
+func TestTruncateWidth(t *testing.T) {
+	tests := []struct {
+		s, tail string
+		cols    int
+		want    string
+	}{
+		{"hello", "…", 10, "hello"},
+		{"hello", "…", 5, "hello"},
+		{"hello world", "…", 6, "hello…"},
+		{"世界世界", "…", 4, "世…"}, // 界 would straddle the limit
+		{"世界世界", "...", 5, "世..."},
+		{"世界世界", "...", 4, "..."},
+		{"hello", "...", 2, "he"}, // Tail wider than cols is left out
+		{"世界", "...", 1, ""},
+		{"hello", "…", 0, ""},
+	}
+	for _, tt := range tests {
+		got := TruncateWidth(tt.s, tt.cols, tt.tail)
+		if got != tt.want {
+			t.Errorf("TruncateWidth(%q, %d, %q) = %q, want %q", tt.s, tt.cols, tt.tail, got, tt.want)
+		}
+		if w := Width(got); w > tt.cols {
+			t.Errorf("TruncateWidth(%q, %d, %q) is %d columns wide", tt.s, tt.cols, tt.tail, w)
+		}
+	}
+}
+
+// Examples run one at a time: leakcheck compares goroutine snapshots, so a
+// parallel test would look like a leak.
+func TestExamplesDoNotLeakOrDeadlock(t *testing.T) {
+	leakcheck.Check(t,
+		leakcheck.Example{Name: "rangeOverStringRevisited", Fn: rangeOverStringRevisited},
+		leakcheck.Example{Name: "clusterExamples", Fn: clusterExamples},
+		leakcheck.Example{Name: "indexMapping", Fn: indexMapping},
+		leakcheck.Example{Name: "truncation", Fn: truncation},
+		leakcheck.Example{Name: "alignedTable", Fn: alignedTable},
+	)
+}
@@ -3,6 +3,8 @@ package main
 import (
 	"fmt"
 	"time"
+
+	"sugar/clock"
 )
 
 func basicWhileLoop() {
@@ -157,7 +159,9 @@ func traditionalForLoop() {
 	}
 }
 
-func retryPattern() {
+// retryPattern sleeps on clk between failed attempts. The test checks on a
+// FakeClock that attempt 2 only starts after the 500ms backoff has passed.
+func retryPattern(clk clock.Clock) {
 	fmt.Println("\n=== 10. RETRY PATTERN ===")
 
 	maxRetries := 3
@@ -174,7 +178,7 @@ func retryPattern() {
 			fmt.Println("Success!")
 		} else {
 			fmt.Println("Failed, retrying...")
-			time.Sleep(500 * time.Millisecond)
+			clk.Sleep(500 * time.Millisecond)
 		}
 	}
 
@@ -214,7 +218,7 @@ func main() {
 	traditionalForLoop()
 	time.Sleep(300 * time.Millisecond)
 
-	retryPattern()
+	retryPattern(clock.Real)
 
 	fmt.Println("\n✅ All examples completed!")
 }
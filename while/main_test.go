@@ -0,0 +1,51 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"sugar/clock"
+
+	"sugar/channels/leakcheck"
+)
+
+func TestRetryPatternBacksOffOnClock(t *testing.T) {
+	clk := clock.NewFakeClock(time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC))
+
+	done := make(chan struct{})
+	go func() {
+		retryPattern(clk)
+		close(done)
+	}()
+
+	clk.BlockUntil(1) // The first attempt failed and is backing off
+	select {
+	case <-done:
+		t.Fatal("retryPattern returned before its backoff elapsed")
+	default:
+	}
+
+	clk.Advance(500 * time.Millisecond)
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("retryPattern did not retry after the backoff")
+	}
+}
+
+// Examples run one at a time: leakcheck compares goroutine snapshots, so a
+// parallel test would look like a leak.
+func TestExamplesDoNotLeakOrDeadlock(t *testing.T) {
+	leakcheck.Check(t,
+		leakcheck.Example{Name: "basicWhileLoop", Fn: basicWhileLoop},
+		leakcheck.Example{Name: "infiniteLoop", Fn: infiniteLoop},
+		leakcheck.Example{Name: "whileWithContinue", Fn: whileWithContinue},
+		leakcheck.Example{Name: "whileMultipleConditions", Fn: whileMultipleConditions},
+		leakcheck.Example{Name: "doWhilePattern", Fn: doWhilePattern},
+		leakcheck.Example{Name: "whileWithChannels", Fn: whileWithChannels},
+		leakcheck.Example{Name: "nestedWhileLoops", Fn: nestedWhileLoops},
+		leakcheck.Example{Name: "whileWithLabel", Fn: whileWithLabel},
+		leakcheck.Example{Name: "traditionalForLoop", Fn: traditionalForLoop},
+		leakcheck.Example{Name: "retryPattern", Fn: func() { retryPattern(clock.Real) }},
+	)
+}